@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/jo-hoe/google-sheets/gs"
+)
+
+// uploadBufferSize is the size of the csv data sent per request by cp
+const uploadBufferSize = 1 << 20
+
+// ls lists the sheets of a spreadsheet ordered by their position
+func (app *cli) ls(ctx context.Context, args []string) error {
+	flagSet := app.newFlags("ls")
+	long := flagSet.Bool("l", false, "list id, grid size and visibility of the sheets")
+	args, err := app.parse(flagSet, args, 1)
+	if err != nil {
+		return err
+	}
+	credentials, opts, err := app.credentials(flagSet)
+	if err != nil {
+		return err
+	}
+
+	spreadSheet, err := gs.OpenSpreadSheet(ctx, args[0], gs.O_RDONLY, credentials, opts...)
+	if err != nil {
+		return err
+	}
+	if !*long {
+		for _, properties := range spreadSheet.Sheets() {
+			fmt.Fprintln(app.stdout, properties.Title)
+		}
+		return nil
+	}
+	writer := tabwriter.NewWriter(app.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tROWS\tCOLUMNS\tHIDDEN\tNAME")
+	for _, properties := range spreadSheet.Sheets() {
+		fmt.Fprintf(writer, "%d\t%d\t%d\t%t\t%s\n", properties.Id, properties.RowCount, properties.ColumnCount, properties.Hidden, properties.Title)
+	}
+	return writer.Flush()
+}
+
+// cat prints the values of a sheet
+func (app *cli) cat(ctx context.Context, args []string) error {
+	flagSet := app.newFlags("cat")
+	format := flagSet.String("format", "csv", "output format: csv, tsv or json (objects keyed by the header row)")
+	args, err := app.parse(flagSet, args, 1)
+	if err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "tsv" && *format != "json" {
+		return fmt.Errorf("unknown format '%s'", *format)
+	}
+	sheetTarget, err := parseSheetArg(args[0])
+	if err != nil {
+		return err
+	}
+	credentials, opts, err := app.credentials(flagSet)
+	if err != nil {
+		return err
+	}
+
+	sheet, err := gs.OpenSheet(ctx, sheetTarget.spreadSheetId, sheetTarget.sheetName, gs.O_RDONLY, credentials, opts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", sheetTarget, err)
+	}
+	switch *format {
+	case "tsv":
+		return writeTsv(ctx, app.stdout, sheet)
+	case "json":
+		return writeJson(ctx, app.stdout, sheet)
+	}
+	_, err = io.Copy(app.stdout, sheet.WithContext(ctx))
+	return err
+}
+
+// cp copies a local csv file to a sheet or a sheet to a local csv file.
+// Exactly one of source and destination has to be a sheet, "-" is stdin or stdout.
+// The flags apply to the destination, which is appended to unless -trunc is given.
+func (app *cli) cp(ctx context.Context, args []string) error {
+	flagSet := app.newFlags("cp")
+	create := flagSet.Bool("create", false, "create the destination if it does not exist")
+	excl := flagSet.Bool("excl", false, "used with -create, the destination must not exist")
+	trunc := flagSet.Bool("trunc", false, "remove the values of the destination before copying")
+	args, err := app.parse(flagSet, args, 2)
+	if err != nil {
+		return err
+	}
+	source, sourceIsSheet := parseTarget(args[0])
+	destination, destinationIsSheet := parseTarget(args[1])
+	if sourceIsSheet == destinationIsSheet {
+		return fmt.Errorf("exactly one of '%s' and '%s' has to be a sheet, expected SPREADSHEET_ID:SHEET", args[0], args[1])
+	}
+	credentials, opts, err := app.credentials(flagSet)
+	if err != nil {
+		return err
+	}
+
+	if destinationIsSheet {
+		flag := gs.O_RDWR
+		if *create {
+			flag |= gs.O_CREATE
+		}
+		if *excl {
+			flag |= gs.O_EXCL
+		}
+		if *trunc {
+			flag |= gs.O_TRUNC
+		}
+		return app.upload(ctx, args[0], destination, flag, credentials, append(opts, gs.WithWriteBuffer(uploadBufferSize)))
+	}
+
+	flag := os.O_WRONLY | os.O_APPEND
+	if *create {
+		flag |= os.O_CREATE
+	}
+	if *excl {
+		flag |= os.O_EXCL
+	}
+	if *trunc {
+		flag |= os.O_TRUNC
+	}
+	return app.download(ctx, source, args[1], flag, credentials, opts)
+}
+
+func (app *cli) upload(ctx context.Context, path string, destination target, flag int, credentials []byte, opts []gs.Option) error {
+	var source io.Reader = app.stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		source = file
+	}
+
+	sheet, err := gs.OpenSheet(ctx, destination.spreadSheetId, destination.sheetName, flag, credentials, opts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", destination, err)
+	}
+	sheet = sheet.WithContext(ctx)
+	if _, err = io.Copy(sheet, source); err != nil {
+		return fmt.Errorf("%s: %w", destination, err)
+	}
+	if err = sheet.Close(); err != nil {
+		return fmt.Errorf("%s: %w", destination, err)
+	}
+	return nil
+}
+
+func (app *cli) download(ctx context.Context, source target, path string, flag int, credentials []byte, opts []gs.Option) (err error) {
+	sheet, err := gs.OpenSheet(ctx, source.spreadSheetId, source.sheetName, gs.O_RDONLY, credentials, opts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+
+	destination := app.stdout
+	if path != "-" {
+		var file io.WriteCloser
+		file, err = app.createFile(path, flag)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			// the data may only be written to disk when the file is closed
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		destination = file
+	}
+	if _, err = io.Copy(destination, sheet.WithContext(ctx)); err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+	return nil
+}
+
+// createFile opens the destination file of a download
+func (app *cli) createFile(path string, flag int) (io.WriteCloser, error) {
+	if app.openFile != nil {
+		return app.openFile(path, flag, 0644)
+	}
+	return os.OpenFile(path, flag, 0644)
+}
+
+// rm removes a sheet, sheets with values are only removed with -force
+func (app *cli) rm(ctx context.Context, args []string) error {
+	flagSet := app.newFlags("rm")
+	force := flagSet.Bool("force", false, "remove the sheet even if it contains values")
+	args, err := app.parse(flagSet, args, 1)
+	if err != nil {
+		return err
+	}
+	sheetTarget, err := parseSheetArg(args[0])
+	if err != nil {
+		return err
+	}
+	credentials, opts, err := app.credentials(flagSet)
+	if err != nil {
+		return err
+	}
+
+	if *force {
+		opts = append(opts, gs.WithForce())
+	}
+	err = gs.RemoveByName(ctx, sheetTarget.spreadSheetId, sheetTarget.sheetName, credentials, opts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", sheetTarget, err)
+	}
+	return nil
+}
+
+// truncate removes all values of a sheet
+func (app *cli) truncate(ctx context.Context, args []string) error {
+	flagSet := app.newFlags("truncate")
+	args, err := app.parse(flagSet, args, 1)
+	if err != nil {
+		return err
+	}
+	sheetTarget, err := parseSheetArg(args[0])
+	if err != nil {
+		return err
+	}
+	credentials, opts, err := app.credentials(flagSet)
+	if err != nil {
+		return err
+	}
+
+	_, err = gs.OpenSheet(ctx, sheetTarget.spreadSheetId, sheetTarget.sheetName, gs.O_RDWR|gs.O_TRUNC, credentials, opts...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", sheetTarget, err)
+	}
+	return nil
+}
+
+func writeTsv(ctx context.Context, writer io.Writer, sheet *gs.Sheet) error {
+	tsvWriter := csv.NewWriter(writer)
+	tsvWriter.Comma = '\t'
+
+	rows := sheet.Rows(ctx)
+	defer rows.Close()
+	for rows.Next() {
+		if err := tsvWriter.Write(rows.Row()); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	tsvWriter.Flush()
+	return tsvWriter.Error()
+}
+
+// writeJson writes the rows below the header as array of objects keyed by the header
+func writeJson(ctx context.Context, writer io.Writer, sheet *gs.Sheet) error {
+	result := make([]map[string]string, 0)
+	var header []string
+
+	rows := sheet.Rows(ctx)
+	defer rows.Close()
+	for rows.Next() {
+		if header == nil {
+			header = rows.Row()
+			continue
+		}
+		object := make(map[string]string)
+		for i, value := range rows.Row() {
+			if i < len(header) {
+				object[header[i]] = value
+			}
+		}
+		result = append(result, object)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
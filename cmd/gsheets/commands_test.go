@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs"
+	"github.com/jo-hoe/google-sheets/gs/gstest"
+)
+
+func newTestCli(server *gstest.Server, stdin string) (*cli, *bytes.Buffer) {
+	stdout := &bytes.Buffer{}
+	return &cli{
+		stdin:      strings.NewReader(stdin),
+		stdout:     stdout,
+		stderr:     &bytes.Buffer{},
+		getenv:     func(string) string { return "" },
+		httpClient: server.Client(),
+	}, stdout
+}
+
+func Test_ls(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	server.AddSheet("spreadSheetId", "Orders", [][]string{{"a"}})
+
+	app, stdout := newTestCli(server, "")
+	err := app.run(context.Background(), []string{"ls", "spreadSheetId"})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if stdout.String() != "Sheet1\nOrders\n" {
+		t.Errorf("found output %q", stdout.String())
+	}
+
+	stdout.Reset()
+	err = app.run(context.Background(), []string{"ls", "-l", "spreadSheetId"})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") || !strings.HasSuffix(lines[2], "Orders") {
+		t.Errorf("found output %q", stdout.String())
+	}
+}
+
+func Test_cat(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Orders", [][]string{{"Id", "Note"}, {"1", "a,b"}, {"2"}})
+
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{name: "csv", format: "csv", expected: "Id,Note\n1,\"a,b\"\n2\n"},
+		{name: "tsv", format: "tsv", expected: "Id\tNote\n1\ta,b\n2\n"},
+		{name: "json", format: "json", expected: "[\n  {\n    \"Id\": \"1\",\n    \"Note\": \"a,b\"\n  },\n  {\n    \"Id\": \"2\"\n  }\n]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, stdout := newTestCli(server, "")
+			err := app.run(context.Background(), []string{"cat", "-format", tt.format, "spreadSheetId:Orders"})
+			if err != nil {
+				t.Fatalf("found error %+v", err)
+			}
+			if stdout.String() != tt.expected {
+				t.Errorf("expected %q, found %q", tt.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func Test_cat_NotExist(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+
+	app, _ := newTestCli(server, "")
+	err := app.run(context.Background(), []string{"cat", "spreadSheetId:Orders"})
+	if !errors.Is(err, gs.ErrNotExist) {
+		t.Errorf("expected %v, found %v", gs.ErrNotExist, err)
+	}
+}
+
+func Test_cp_Upload(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Orders", [][]string{{"old"}})
+
+	path := filepath.Join(t.TempDir(), "orders.csv")
+	if err := os.WriteFile(path, []byte("a,b\n1,2\n"), 0644); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	app, _ := newTestCli(server, "")
+	// without -create the sheet has to exist
+	err := app.run(context.Background(), []string{"cp", path, "spreadSheetId:New"})
+	if !errors.Is(err, gs.ErrNotExist) {
+		t.Errorf("expected %v, found %v", gs.ErrNotExist, err)
+	}
+	err = app.run(context.Background(), []string{"cp", "-create", "-excl", path, "spreadSheetId:Orders"})
+	if !errors.Is(err, gs.ErrExist) {
+		t.Errorf("expected %v, found %v", gs.ErrExist, err)
+	}
+
+	err = app.run(context.Background(), []string{"cp", "-create", path, "spreadSheetId:New"})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertValues(t, [][]string{{"a", "b"}, {"1", "2"}}, server.Values("spreadSheetId", "New"))
+
+	err = app.run(context.Background(), []string{"cp", "-trunc", path, "spreadSheetId:Orders"})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertValues(t, [][]string{{"a", "b"}, {"1", "2"}}, server.Values("spreadSheetId", "Orders"))
+
+	// stdin is appended without -trunc
+	app, _ = newTestCli(server, "3,4")
+	err = app.run(context.Background(), []string{"cp", "-", "spreadSheetId:Orders"})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertValues(t, [][]string{{"a", "b"}, {"1", "2"}, {"3", "4"}}, server.Values("spreadSheetId", "Orders"))
+}
+
+func Test_cp_Download(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Orders", [][]string{{"a", "b"}})
+
+	path := filepath.Join(t.TempDir(), "orders.csv")
+	app, stdout := newTestCli(server, "")
+	err := app.run(context.Background(), []string{"cp", "spreadSheetId:Orders", path})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected %v, found %v", os.ErrNotExist, err)
+	}
+
+	err = app.run(context.Background(), []string{"cp", "-create", "spreadSheetId:Orders", path})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	err = app.run(context.Background(), []string{"cp", "spreadSheetId:Orders", path})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertFile(t, "a,b\na,b\n", path)
+
+	err = app.run(context.Background(), []string{"cp", "-trunc", "spreadSheetId:Orders", path})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertFile(t, "a,b\n", path)
+
+	err = app.run(context.Background(), []string{"cp", "-create", "-excl", "spreadSheetId:Orders", path})
+	if !errors.Is(err, os.ErrExist) {
+		t.Errorf("expected %v, found %v", os.ErrExist, err)
+	}
+
+	err = app.run(context.Background(), []string{"cp", "spreadSheetId:Orders", "-"})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if stdout.String() != "a,b\n" {
+		t.Errorf("found output %q", stdout.String())
+	}
+}
+
+type failingCloser struct {
+	bytes.Buffer
+}
+
+func (closer *failingCloser) Close() error {
+	return errors.New("no space left on device")
+}
+
+func Test_cp_Download_Close_Failed(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Orders", [][]string{{"a", "b"}})
+
+	app, _ := newTestCli(server, "")
+	file := &failingCloser{}
+	app.openFile = func(string, int, os.FileMode) (io.WriteCloser, error) {
+		return file, nil
+	}
+	err := app.run(context.Background(), []string{"cp", "-create", "spreadSheetId:Orders", "orders.csv"})
+	if err == nil || err.Error() != "no space left on device" {
+		t.Errorf("expected close error, found %v", err)
+	}
+	if file.String() != "a,b\n" {
+		t.Errorf("found output %q", file.String())
+	}
+}
+
+func Test_cp_Invalid(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+
+	app, _ := newTestCli(server, "")
+	err := app.run(context.Background(), []string{"cp", "a.csv", "b.csv"})
+	if err == nil {
+		t.Errorf("expected error")
+	}
+	err = app.run(context.Background(), []string{"cp", "a.csv"})
+	if !errors.Is(err, errUsage) {
+		t.Errorf("expected %v, found %v", errUsage, err)
+	}
+}
+
+func Test_rm(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	server.AddSheet("spreadSheetId", "Orders", [][]string{{"a"}})
+
+	app, _ := newTestCli(server, "")
+	err := app.run(context.Background(), []string{"rm", "spreadSheetId:Orders"})
+	if !errors.Is(err, gs.ErrNotEmpty) {
+		t.Errorf("expected %v, found %v", gs.ErrNotEmpty, err)
+	}
+	err = app.run(context.Background(), []string{"rm", "-force", "spreadSheetId:Orders"})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if !reflect.DeepEqual([]string{"Sheet1"}, server.SheetNames("spreadSheetId")) {
+		t.Errorf("found sheets %v", server.SheetNames("spreadSheetId"))
+	}
+}
+
+func Test_truncate(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Orders", [][]string{{"a"}})
+
+	app, _ := newTestCli(server, "")
+	err := app.run(context.Background(), []string{"truncate", "spreadSheetId:Orders"})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertValues(t, [][]string{}, server.Values("spreadSheetId", "Orders"))
+}
+
+func assertValues(t *testing.T, expected [][]string, actual [][]string) {
+	t.Helper()
+	if len(expected) == 0 && len(actual) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, found %v", expected, actual)
+	}
+}
+
+func assertFile(t *testing.T, expected string, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if string(data) != expected {
+		t.Errorf("expected %q, found %q", expected, string(data))
+	}
+}
@@ -0,0 +1,203 @@
+// Command gsheets reads and writes Google Sheets from the command line.
+//
+// Usage:
+//
+//	gsheets <command> [flags] <arguments>
+//
+// The commands are:
+//
+//	ls        list the sheets of a spreadsheet
+//	cat       print a sheet as csv, tsv or json
+//	cp        copy a local csv file to a sheet or a sheet to a local csv file
+//	rm        remove a sheet
+//	truncate  remove all values of a sheet
+//
+// Sheets are addressed as SPREADSHEET_ID:SHEET_NAME, e.g. "1yxmv2lTtOtvpkBi:Orders".
+// The service account key file is taken from the -credentials flag or
+// the GOOGLE_APPLICATION_CREDENTIALS environment variable.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+
+	"github.com/jo-hoe/google-sheets/gs"
+)
+
+const credentialsEnv = "GOOGLE_APPLICATION_CREDENTIALS"
+
+// errUsage is returned if the command line is invalid, the usage was already printed
+var errUsage = errors.New("invalid usage")
+
+// spreadSheetIdPattern matches the characters of spreadsheet ids as they appear in urls
+var spreadSheetIdPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{2,}$`)
+
+// cli holds the environment of a command
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+	// httpClient replaces the credentials if set, used by tests
+	httpClient *http.Client
+	// openFile replaces os.OpenFile for downloads if set, used by tests
+	openFile func(name string, flag int, perm os.FileMode) (io.WriteCloser, error)
+}
+
+type command struct {
+	name  string
+	usage string
+	run   func(app *cli, ctx context.Context, args []string) error
+}
+
+// commands is set by init, since the commands refer to it to print their usage
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "ls", usage: "ls [-l] SPREADSHEET_ID", run: (*cli).ls},
+		{name: "cat", usage: "cat [-format csv|tsv|json] SPREADSHEET_ID:SHEET", run: (*cli).cat},
+		{name: "cp", usage: "cp [-create] [-excl] [-trunc] SOURCE DESTINATION", run: (*cli).cp},
+		{name: "rm", usage: "rm [-force] SPREADSHEET_ID:SHEET", run: (*cli).rm},
+		{name: "truncate", usage: "truncate SPREADSHEET_ID:SHEET", run: (*cli).truncate},
+	}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	app := &cli{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}
+	err := app.run(ctx, os.Args[1:])
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gsheets: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func (app *cli) run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		app.usage()
+		return errUsage
+	}
+	for _, command := range commands {
+		if command.name == args[0] {
+			return command.run(app, ctx, args[1:])
+		}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		app.usage()
+		return nil
+	}
+	fmt.Fprintf(app.stderr, "gsheets: unknown command '%s'\n", args[0])
+	app.usage()
+	return errUsage
+}
+
+func (app *cli) usage() {
+	fmt.Fprintln(app.stderr, "usage: gsheets <command> [flags] <arguments>")
+	fmt.Fprintln(app.stderr)
+	for _, command := range commands {
+		fmt.Fprintf(app.stderr, "  gsheets %s\n", command.usage)
+	}
+	fmt.Fprintln(app.stderr)
+	fmt.Fprintf(app.stderr, "Each command accepts -credentials FILE, by default %s is used.\n", credentialsEnv)
+}
+
+// flags is the flag set of a command including the common flags
+type flags struct {
+	*flag.FlagSet
+	credentials string
+}
+
+func (app *cli) newFlags(name string) *flags {
+	result := &flags{FlagSet: flag.NewFlagSet(name, flag.ContinueOnError)}
+	result.SetOutput(app.stderr)
+	result.StringVar(&result.credentials, "credentials", "", "service account key file (default $"+credentialsEnv+")")
+	result.Usage = func() {
+		for _, command := range commands {
+			if command.name == name {
+				fmt.Fprintf(app.stderr, "usage: gsheets %s\n", command.usage)
+			}
+		}
+		result.PrintDefaults()
+	}
+	return result
+}
+
+// parse parses the arguments of a command which expects the given number of positional arguments
+func (app *cli) parse(flagSet *flags, args []string, count int) ([]string, error) {
+	err := flagSet.Parse(args)
+	if err != nil {
+		return nil, errUsage
+	}
+	if flagSet.NArg() != count {
+		flagSet.Usage()
+		return nil, errUsage
+	}
+	return flagSet.Args(), nil
+}
+
+// credentials returns the service account key and the options used for all requests
+func (app *cli) credentials(flagSet *flags) ([]byte, []gs.Option, error) {
+	if app.httpClient != nil {
+		return nil, []gs.Option{gs.WithHTTPClient(app.httpClient)}, nil
+	}
+	path := flagSet.credentials
+	if path == "" {
+		path = app.getenv(credentialsEnv)
+	}
+	if path == "" {
+		return nil, nil, fmt.Errorf("no credentials, use -credentials or set %s", credentialsEnv)
+	}
+	credentials, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return credentials, nil, nil
+}
+
+// target is a sheet given as SPREADSHEET_ID:SHEET
+type target struct {
+	spreadSheetId string
+	sheetName     string
+}
+
+func (t target) String() string {
+	return t.spreadSheetId + ":" + t.sheetName
+}
+
+// parseTarget splits a SPREADSHEET_ID:SHEET argument. The second result is false if
+// the argument is no sheet, e.g. a local path. Single letters are treated as
+// drive letters of windows paths.
+func parseTarget(arg string) (target, bool) {
+	spreadSheetId, sheetName, found := strings.Cut(arg, ":")
+	if !found || sheetName == "" || !spreadSheetIdPattern.MatchString(spreadSheetId) {
+		return target{}, false
+	}
+	return target{spreadSheetId: spreadSheetId, sheetName: sheetName}, true
+}
+
+func parseSheetArg(arg string) (target, error) {
+	result, ok := parseTarget(arg)
+	if !ok {
+		return target{}, fmt.Errorf("'%s' is no sheet, expected SPREADSHEET_ID:SHEET", arg)
+	}
+	return result, nil
+}
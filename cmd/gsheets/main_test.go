@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_parseTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		arg      string
+		expected target
+		ok       bool
+	}{
+		{name: "sheet", arg: "1yxmv2lTtOtvpkBi:Orders", expected: target{"1yxmv2lTtOtvpkBi", "Orders"}, ok: true},
+		{name: "colon in sheet name", arg: "1yxmv2lT-_:Q1: Orders", expected: target{"1yxmv2lT-_", "Q1: Orders"}, ok: true},
+		{name: "local file", arg: "orders.csv", ok: false},
+		{name: "relative path", arg: "./data:orders.csv", ok: false},
+		{name: "windows path", arg: `C:\data\orders.csv`, ok: false},
+		{name: "missing sheet name", arg: "1yxmv2lTtOtvpkBi:", ok: false},
+		{name: "stdin", arg: "-", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := parseTarget(tt.arg)
+			if ok != tt.ok || actual != tt.expected {
+				t.Errorf("expected %v %v, found %v %v", tt.expected, tt.ok, actual, ok)
+			}
+		})
+	}
+}
+
+func Test_run_Usage(t *testing.T) {
+	stderr := &bytes.Buffer{}
+	app := &cli{stderr: stderr, getenv: func(string) string { return "" }}
+
+	err := app.run(context.Background(), []string{"unknown"})
+	if !errors.Is(err, errUsage) {
+		t.Errorf("expected %v, found %v", errUsage, err)
+	}
+	if !strings.Contains(stderr.String(), "gsheets truncate") {
+		t.Errorf("expected usage, found %q", stderr.String())
+	}
+}
+
+func Test_credentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	environment := map[string]string{}
+	app := &cli{stderr: &bytes.Buffer{}, getenv: func(key string) string { return environment[key] }}
+
+	flagSet := app.newFlags("ls")
+	if _, _, err := app.credentials(flagSet); err == nil {
+		t.Errorf("expected error without credentials")
+	}
+
+	environment[credentialsEnv] = path
+	credentials, _, err := app.credentials(flagSet)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if string(credentials) != "{}" {
+		t.Errorf("found credentials %q", credentials)
+	}
+
+	environment[credentialsEnv] = filepath.Join(t.TempDir(), "missing.json")
+	if err = flagSet.Parse([]string{"-credentials", path}); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if _, _, err = app.credentials(flagSet); err != nil {
+		t.Errorf("expected flag to take precedence, found %+v", err)
+	}
+}
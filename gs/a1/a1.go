@@ -0,0 +1,159 @@
+// Package a1 implements the A1 notation used by Google Sheets to reference cells,
+// see https://developers.google.com/sheets/api/guides/concepts#cell
+package a1
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxColumn is the highest column supported by Google Sheets ("ZZZ")
+const MaxColumn = 18278
+
+var ErrSyntax = errors.New("invalid A1 notation")
+
+// Cell references a single cell. Column and row are one based, i.e. "A1" is {Column: 1, Row: 1}.
+type Cell struct {
+	Column int
+	Row    int
+}
+
+// ParseCell parses a cell reference like "B2"
+func ParseCell(value string) (Cell, error) {
+	letters, digits := splitLettersDigits(value)
+	if letters == "" || digits == "" || len(letters)+len(digits) != len(value) {
+		return Cell{}, fmt.Errorf("%w: cell '%s'", ErrSyntax, value)
+	}
+	column, err := LettersToColumn(letters)
+	if err != nil {
+		return Cell{}, err
+	}
+	row, err := strconv.Atoi(digits)
+	if err != nil || row < 1 {
+		return Cell{}, fmt.Errorf("%w: row of cell '%s'", ErrSyntax, value)
+	}
+	return Cell{Column: column, Row: row}, nil
+}
+
+// String formats the cell in A1 notation e.g. "B2"
+func (cell Cell) String() string {
+	return ColumnToLetters(cell.Column) + strconv.Itoa(cell.Row)
+}
+
+// ColumnToLetters converts a one based column number into letters, e.g. 1 to "A" and 27 to "AA"
+func ColumnToLetters(column int) string {
+	if column < 1 {
+		return ""
+	}
+	result := ""
+	for column > 0 {
+		column--
+		result = string(rune('A'+column%26)) + result
+		column = column / 26
+	}
+	return result
+}
+
+// LettersToColumn converts column letters into a one based column number, e.g. "AA" to 27.
+// Lower case letters are accepted.
+func LettersToColumn(letters string) (int, error) {
+	if letters == "" {
+		return 0, fmt.Errorf("%w: empty column", ErrSyntax)
+	}
+	column := 0
+	for _, letter := range strings.ToUpper(letters) {
+		if letter < 'A' || letter > 'Z' {
+			return 0, fmt.Errorf("%w: column '%s'", ErrSyntax, letters)
+		}
+		column = column*26 + int(letter-'A') + 1
+		if column > MaxColumn {
+			return 0, fmt.Errorf("%w: column '%s' exceeds %s", ErrSyntax, letters, ColumnToLetters(MaxColumn))
+		}
+	}
+	return column, nil
+}
+
+// QuoteSheetName quotes a sheet name for the usage in A1 notation if required.
+// Names containing other characters than letters, digits and underscores,
+// or names which could be confused with a cell reference are enclosed in
+// single quotes. Single quotes within the name are escaped by doubling them.
+func QuoteSheetName(name string) string {
+	if isPlainSheetName(name) {
+		return name
+	}
+	return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+}
+
+// UnquoteSheetName reverses QuoteSheetName
+func UnquoteSheetName(name string) (string, error) {
+	if !strings.HasPrefix(name, "'") {
+		if strings.Contains(name, "'") {
+			return "", fmt.Errorf("%w: unquoted sheet name '%s' contains a quote", ErrSyntax, name)
+		}
+		return name, nil
+	}
+	if len(name) < 2 || !strings.HasSuffix(name, "'") {
+		return "", fmt.Errorf("%w: unterminated sheet name %s", ErrSyntax, name)
+	}
+	inner := name[1 : len(name)-1]
+	if strings.Contains(strings.ReplaceAll(inner, "''", ""), "'") {
+		return "", fmt.Errorf("%w: unescaped quote in sheet name %s", ErrSyntax, name)
+	}
+	return strings.ReplaceAll(inner, "''", "'"), nil
+}
+
+func isPlainSheetName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, character := range name {
+		isLetter := (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z') || character == '_'
+		isDigit := character >= '0' && character <= '9'
+		if !isLetter && !(isDigit && i > 0) {
+			return false
+		}
+	}
+	// names like "AB12", "ABC" or "R1C1" would be read as a cell or column reference
+	if _, err := ParseCell(name); err == nil {
+		return false
+	}
+	if _, err := LettersToColumn(name); err == nil {
+		return false
+	}
+	return !isR1C1(name)
+}
+
+func isR1C1(value string) bool {
+	upper := strings.ToUpper(value)
+	if !strings.HasPrefix(upper, "R") {
+		return false
+	}
+	row, column, found := strings.Cut(upper[1:], "C")
+	return found && isDigits(row) && isDigits(column)
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, character := range value {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func splitLettersDigits(value string) (letters string, digits string) {
+	i := 0
+	for i < len(value) && ((value[i] >= 'A' && value[i] <= 'Z') || (value[i] >= 'a' && value[i] <= 'z')) {
+		i++
+	}
+	j := i
+	for j < len(value) && value[j] >= '0' && value[j] <= '9' {
+		j++
+	}
+	return value[:i], value[i:j]
+}
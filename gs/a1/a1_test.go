@@ -0,0 +1,92 @@
+package a1
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestColumnToLetters(t *testing.T) {
+	tests := map[int]string{1: "A", 26: "Z", 27: "AA", 52: "AZ", 702: "ZZ", 703: "AAA", MaxColumn: "ZZZ", 0: ""}
+	for column, expected := range tests {
+		if actual := ColumnToLetters(column); actual != expected {
+			t.Errorf("expected '%s' for %d but found '%s'", expected, column, actual)
+		}
+	}
+}
+
+func TestLettersToColumn(t *testing.T) {
+	for column := 1; column <= MaxColumn; column++ {
+		actual, err := LettersToColumn(ColumnToLetters(column))
+		if err != nil {
+			t.Fatalf("found error %v", err)
+		}
+		if actual != column {
+			t.Fatalf("expected %d but found %d", column, actual)
+		}
+	}
+
+	for _, invalid := range []string{"", "A1", "AAAA", "Ä"} {
+		if _, err := LettersToColumn(invalid); !errors.Is(err, ErrSyntax) {
+			t.Errorf("expected syntax error for '%s' but found %v", invalid, err)
+		}
+	}
+
+	if actual, _ := LettersToColumn("ab"); actual != 28 {
+		t.Errorf("expected lower case letters to be accepted but found %d", actual)
+	}
+}
+
+func TestParseCell(t *testing.T) {
+	actual, err := ParseCell("AB12")
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+	if actual != (Cell{Column: 28, Row: 12}) {
+		t.Errorf("unexpected cell %+v", actual)
+	}
+	if actual.String() != "AB12" {
+		t.Errorf("expected 'AB12' but found '%s'", actual.String())
+	}
+
+	for _, invalid := range []string{"", "A", "12", "A0", "1A", "A1B", "A-1"} {
+		if _, err := ParseCell(invalid); !errors.Is(err, ErrSyntax) {
+			t.Errorf("expected syntax error for '%s' but found %v", invalid, err)
+		}
+	}
+}
+
+func TestQuoteSheetName(t *testing.T) {
+	tests := map[string]string{
+		"Sheet1":        "Sheet1",
+		"Data_2024":     "Data_2024",
+		"My Sheet":      "'My Sheet'",
+		"Bob's Data":    "'Bob''s Data'",
+		"A1":            "'A1'",
+		"ABC":           "'ABC'",
+		"R1C1":          "'R1C1'",
+		"2024":          "'2024'",
+		"Sales!Summary": "'Sales!Summary'",
+		"":              "''",
+	}
+	for name, expected := range tests {
+		actual := QuoteSheetName(name)
+		if actual != expected {
+			t.Errorf("expected %s for '%s' but found %s", expected, name, actual)
+		}
+		unquoted, err := UnquoteSheetName(actual)
+		if err != nil {
+			t.Errorf("found error %v", err)
+		}
+		if unquoted != name {
+			t.Errorf("expected '%s' after unquoting but found '%s'", name, unquoted)
+		}
+	}
+}
+
+func TestUnquoteSheetName_Invalid(t *testing.T) {
+	for _, invalid := range []string{"'", "'abc", "a'b", "'a'b'"} {
+		if _, err := UnquoteSheetName(invalid); !errors.Is(err, ErrSyntax) {
+			t.Errorf("expected syntax error for %s but found %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package a1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Range references a rectangular region of a sheet, e.g. "Data!B2:F500".
+//
+// A zero Column or Row of Start or End means that the range is unbounded in
+// that direction, e.g. "Data!A:B" has no rows set and "Data!A5:B" has no end row.
+// A range without any cells references the whole sheet.
+type Range struct {
+	// name of the sheet, may be empty if the range is relative to a sheet
+	Sheet string
+	Start Cell
+	End   Cell
+}
+
+// ParseRange parses a range in A1 notation. The following forms are supported:
+//
+//	Sheet1             the whole sheet
+//	'My Sheet'!A1:B2   a quoted sheet name with a block of cells
+//	A1:B2              cells without a sheet name
+//	Sheet1!A1          a single cell
+//	Sheet1!A:B         whole columns
+//	Sheet1!2:5         whole rows
+//	Sheet1!A5:B        columns starting at a row
+//
+// A name without cells is always interpreted as sheet name. Whether it
+// references a named range instead can only be decided by the spreadsheet.
+func ParseRange(value string) (Range, error) {
+	sheetPart, cellPart, hasCells, err := splitSheet(value)
+	if err != nil {
+		return Range{}, err
+	}
+
+	result := Range{}
+	if sheetPart != "" {
+		result.Sheet, err = UnquoteSheetName(sheetPart)
+		if err != nil {
+			return Range{}, err
+		}
+	}
+	if !hasCells {
+		return result, nil
+	}
+
+	startPart, endPart, isBlock := strings.Cut(cellPart, ":")
+	if !isBlock {
+		// a single reference has to be a complete cell
+		result.Start, err = ParseCell(startPart)
+		result.End = result.Start
+		return result, err
+	}
+	result.Start, err = parsePartialCell(startPart)
+	if err != nil {
+		return Range{}, err
+	}
+	result.End, err = parsePartialCell(endPart)
+	if err != nil {
+		return Range{}, err
+	}
+	if (result.Start.Column == 0) != (result.End.Column == 0) && result.End.Row != 0 {
+		return Range{}, fmt.Errorf("%w: range '%s' mixes rows and cells", ErrSyntax, value)
+	}
+	if result.Start.Column == 0 && result.Start.Row == 0 {
+		return Range{}, fmt.Errorf("%w: range '%s'", ErrSyntax, value)
+	}
+	return result, nil
+}
+
+// String formats the range in A1 notation quoting the sheet name if needed
+func (r Range) String() string {
+	cells := r.cells()
+	if r.Sheet == "" {
+		return cells
+	}
+	if cells == "" {
+		return QuoteSheetName(r.Sheet)
+	}
+	return QuoteSheetName(r.Sheet) + "!" + cells
+}
+
+// IsWholeSheet returns true if the range does not restrict the cells of the sheet
+func (r Range) IsWholeSheet() bool {
+	return r.Start == (Cell{}) && r.End == (Cell{})
+}
+
+// StartRow returns the first row of the range, which is 1 for ranges without start row
+func (r Range) StartRow() int {
+	if r.Start.Row == 0 {
+		return 1
+	}
+	return r.Start.Row
+}
+
+// StartColumn returns the first column of the range, which is 1 for ranges without start column
+func (r Range) StartColumn() int {
+	if r.Start.Column == 0 {
+		return 1
+	}
+	return r.Start.Column
+}
+
+func (r Range) cells() string {
+	if r.IsWholeSheet() {
+		return ""
+	}
+	if r.Start == r.End && r.Start.Column != 0 && r.Start.Row != 0 {
+		return r.Start.String()
+	}
+	return formatPartialCell(r.Start) + ":" + formatPartialCell(r.End)
+}
+
+// splitSheet splits a range into the (still quoted) sheet name and the cells
+func splitSheet(value string) (sheetPart string, cellPart string, hasCells bool, err error) {
+	if value == "" {
+		return "", "", false, fmt.Errorf("%w: empty range", ErrSyntax)
+	}
+	if strings.HasPrefix(value, "'") {
+		// find the closing quote, quotes within the name are doubled
+		end := 1
+		for ; end < len(value); end++ {
+			if value[end] != '\'' {
+				continue
+			}
+			if end+1 < len(value) && value[end+1] == '\'' {
+				end++
+				continue
+			}
+			break
+		}
+		if end >= len(value) {
+			return "", "", false, fmt.Errorf("%w: unterminated sheet name %s", ErrSyntax, value)
+		}
+		rest := value[end+1:]
+		if rest == "" {
+			return value, "", false, nil
+		}
+		if !strings.HasPrefix(rest, "!") {
+			return "", "", false, fmt.Errorf("%w: range %s", ErrSyntax, value)
+		}
+		return value[:end+1], rest[1:], true, nil
+	}
+
+	if sheetPart, cellPart, found := strings.Cut(value, "!"); found {
+		return sheetPart, cellPart, true, nil
+	}
+	// without sheet name the value is either a reference to cells or a sheet name
+	if _, err := ParseCell(value); err == nil || strings.Contains(value, ":") {
+		return "", value, true, nil
+	}
+	return value, "", false, nil
+}
+
+// parsePartialCell parses cells of which either column or row may be missing, e.g. "A" or "5"
+func parsePartialCell(value string) (Cell, error) {
+	letters, digits := splitLettersDigits(value)
+	if len(letters)+len(digits) != len(value) || value == "" {
+		return Cell{}, fmt.Errorf("%w: cell '%s'", ErrSyntax, value)
+	}
+	result := Cell{}
+	var err error
+	if letters != "" {
+		result.Column, err = LettersToColumn(letters)
+		if err != nil {
+			return Cell{}, err
+		}
+	}
+	if digits != "" {
+		result.Row, err = strconv.Atoi(digits)
+		if err != nil || result.Row < 1 {
+			return Cell{}, fmt.Errorf("%w: row of cell '%s'", ErrSyntax, value)
+		}
+	}
+	return result, nil
+}
+
+func formatPartialCell(cell Cell) string {
+	result := ColumnToLetters(cell.Column)
+	if cell.Row > 0 {
+		result += strconv.Itoa(cell.Row)
+	}
+	return result
+}
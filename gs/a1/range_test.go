@@ -0,0 +1,70 @@
+package a1
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   Range
+		format string
+	}{
+		{value: "Sheet1", want: Range{Sheet: "Sheet1"}},
+		{value: "'My Sheet'", want: Range{Sheet: "My Sheet"}},
+		{value: "Data!B2:F500", want: Range{Sheet: "Data", Start: Cell{2, 2}, End: Cell{6, 500}}},
+		{value: "'Bob''s Data'!A1", want: Range{Sheet: "Bob's Data", Start: Cell{1, 1}, End: Cell{1, 1}}},
+		{value: "'a!b'!A1:B2", want: Range{Sheet: "a!b", Start: Cell{1, 1}, End: Cell{2, 2}}},
+		{value: "A1:B2", want: Range{Start: Cell{1, 1}, End: Cell{2, 2}}},
+		{value: "C3", want: Range{Start: Cell{3, 3}, End: Cell{3, 3}}},
+		{value: "Data!A:C", want: Range{Sheet: "Data", Start: Cell{Column: 1}, End: Cell{Column: 3}}},
+		{value: "Data!2:5", want: Range{Sheet: "Data", Start: Cell{Row: 2}, End: Cell{Row: 5}}},
+		{value: "Data!A5:C", want: Range{Sheet: "Data", Start: Cell{1, 5}, End: Cell{Column: 3}}},
+		{value: "data!b2:c3", want: Range{Sheet: "data", Start: Cell{2, 2}, End: Cell{3, 3}}, format: "data!B2:C3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRange(tt.value)
+			if err != nil {
+				t.Fatalf("found error %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v but found %+v", tt.want, got)
+			}
+			format := tt.format
+			if format == "" {
+				format = tt.value
+			}
+			if got.String() != format {
+				t.Errorf("expected '%s' but found '%s'", format, got.String())
+			}
+		})
+	}
+}
+
+func TestParseRange_Invalid(t *testing.T) {
+	for _, invalid := range []string{"", "'Sheet", "'Sheet'A1", "Data!", "Data!A1:", "Data!A0", "Data!1:B2", "Data!A:5", "Data!A1:5", "Data!A1:B2:C3"} {
+		if _, err := ParseRange(invalid); !errors.Is(err, ErrSyntax) {
+			t.Errorf("expected syntax error for '%s' but found %v", invalid, err)
+		}
+	}
+}
+
+func TestRange_Helpers(t *testing.T) {
+	r, err := ParseRange("B2:F500")
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+	if r.IsWholeSheet() {
+		t.Error("expected range not to be the whole sheet")
+	}
+	if r.StartRow() != 2 || r.StartColumn() != 2 {
+		t.Errorf("unexpected dimensions of %+v", r)
+	}
+
+	whole := Range{Sheet: "Data"}
+	if !whole.IsWholeSheet() || whole.StartRow() != 1 {
+		t.Errorf("unexpected dimensions of %+v", whole)
+	}
+}
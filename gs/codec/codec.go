@@ -0,0 +1,154 @@
+// Package codec maps sheet rows to Go structs and back.
+//
+// The first row of a sheet is the header. Each struct field is mapped to the
+// column named by its `sheet` tag, or to the column named like the field if
+// the tag is missing:
+//
+//	type Order struct {
+//		Id      int       `sheet:"Order Id"`
+//		Amount  float64   `sheet:"Amount"`
+//		Paid    bool      `sheet:"Paid"`
+//		Date    time.Time `sheet:"Date,layout=2006-01-02"`
+//		Note    *string   `sheet:"Note"`
+//		Ignored string    `sheet:"-"`
+//	}
+//
+// Supported field types are strings, integers, floats, booleans, time.Time,
+// types implementing encoding.TextMarshaler and encoding.TextUnmarshaler, and
+// pointers to them. Empty cells decode to nil pointers or zero values.
+//
+// A time.Time field without layout option decodes RFC 3339, "2006-01-02 15:04:05",
+// "2006-01-02" and serial numbers, the days since 1899-12-30 which Sheets returns
+// for dates with the render option UNFORMATTED_VALUE. Formatted values depend on the
+// locale of the spreadsheet and need a layout option matching the number format.
+package codec
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+)
+
+// DefaultTimeLayout is used to encode time.Time fields without layout option
+const DefaultTimeLayout = time.RFC3339
+
+// timeLayouts are tried in order to decode time.Time fields without layout option
+var timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// serialEpoch is day 0 of the serial numbers of dates in Sheets
+var serialEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+var ErrUnsupportedType = errors.New("unsupported type")
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// CellError reports a cell which could not be decoded
+type CellError struct {
+	// Row and Column are one based and include the header row
+	Row    int
+	Column int
+	// Header is the name of the column
+	Header string
+	Value  string
+	Err    error
+}
+
+func (err *CellError) Error() string {
+	return fmt.Sprintf("cannot decode '%s' in cell %s (column '%s'): %v",
+		err.Value, a1.Cell{Column: err.Column, Row: err.Row}.String(), err.Header, err.Err)
+}
+
+func (err *CellError) Unwrap() error {
+	return err.Err
+}
+
+// field describes how a struct field is mapped to a column
+type field struct {
+	name   string
+	index  []int
+	layout string
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// fieldsOf returns the mapped fields of a struct type in declaration order
+func fieldsOf(structType reflect.Type) ([]field, error) {
+	if cached, ok := fieldCache.Load(structType); ok {
+		return cached.([]field), nil
+	}
+
+	result := make([]field, 0, structType.NumField())
+	names := make(map[string]bool)
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		tag := structField.Tag.Get("sheet")
+		if tag == "-" {
+			continue
+		}
+		name, tagOptions, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = structField.Name
+		}
+		if names[name] {
+			return nil, fmt.Errorf("column '%s' is mapped twice in %s", name, structType)
+		}
+		names[name] = true
+
+		if !isSupported(structField.Type) {
+			return nil, fmt.Errorf("%w %s of field %s", ErrUnsupportedType, structField.Type, structField.Name)
+		}
+		mapped := field{name: name, index: structField.Index}
+		for _, option := range strings.Split(tagOptions, ",") {
+			if layout, found := strings.CutPrefix(option, "layout="); found {
+				mapped.layout = layout
+			}
+		}
+		result = append(result, mapped)
+	}
+
+	fieldCache.Store(structType, result)
+	return result, nil
+}
+
+func isSupported(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType == timeType ||
+		reflect.PointerTo(fieldType).Implements(textUnmarshalerType) && fieldType.Implements(textMarshalerType) {
+		return true
+	}
+	switch fieldType.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// structType returns the struct type of values like []T, []*T, T or *T
+func structType(valueType reflect.Type) (reflect.Type, error) {
+	for valueType.Kind() == reflect.Pointer || valueType.Kind() == reflect.Slice {
+		valueType = valueType.Elem()
+	}
+	if valueType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w %s, expected a struct", ErrUnsupportedType, valueType)
+	}
+	return valueType, nil
+}
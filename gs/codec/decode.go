@@ -0,0 +1,166 @@
+package codec
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decode maps records to structs. The first record is the header.
+// dst has to be a pointer to a slice of structs (or pointers to structs).
+// Columns without field and fields without column are ignored.
+// The first cell which cannot be converted is reported as *CellError.
+func Decode(records [][]string, dst any) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w %T, expected a pointer to a slice", ErrUnsupportedType, dst)
+	}
+	slice := target.Elem()
+	elementType := slice.Type().Elem()
+	structType, err := structType(elementType)
+	if err != nil {
+		return err
+	}
+	fields, err := fieldsOf(structType)
+	if err != nil {
+		return err
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(records))
+	if len(records) > 0 {
+		columns := columnsOf(records[0], fields)
+		for i, record := range records[1:] {
+			element := reflect.New(structType).Elem()
+			if err := decodeRecord(record, records[0], columns, fields, element); err != nil {
+				var cellError *CellError
+				if errors.As(err, &cellError) {
+					// the header is the first row
+					cellError.Row = i + 2
+				}
+				return err
+			}
+			if elementType.Kind() == reflect.Pointer {
+				element = element.Addr()
+			}
+			result = reflect.Append(result, element)
+		}
+	}
+	slice.Set(result)
+	return nil
+}
+
+// columnsOf returns the zero based column of each field, -1 if the header does not contain the field
+func columnsOf(header []string, fields []field) []int {
+	result := make([]int, len(fields))
+	for i, field := range fields {
+		result[i] = -1
+		for column, name := range header {
+			if strings.TrimSpace(name) == field.name {
+				result[i] = column
+				break
+			}
+		}
+	}
+	return result
+}
+
+func decodeRecord(record []string, header []string, columns []int, fields []field, element reflect.Value) error {
+	for i, field := range fields {
+		column := columns[i]
+		if column < 0 || column >= len(record) {
+			continue
+		}
+		value := record[column]
+		if err := decodeValue(value, field.layout, element.FieldByIndex(field.index)); err != nil {
+			return &CellError{Column: column + 1, Header: header[column], Value: value, Err: err}
+		}
+	}
+	return nil
+}
+
+func decodeValue(value string, layout string, target reflect.Value) error {
+	if target.Kind() == reflect.Pointer {
+		if value == "" {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		pointer := reflect.New(target.Type().Elem())
+		if err := decodeValue(value, layout, pointer.Elem()); err != nil {
+			return err
+		}
+		target.Set(pointer)
+		return nil
+	}
+
+	if target.Type() == timeType {
+		return decodeTime(value, layout, target)
+	}
+	if unmarshaler, ok := target.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(value))
+	}
+	if value == "" {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		target.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, target.Type().Bits())
+		if err != nil {
+			return err
+		}
+		target.SetFloat(parsed)
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedType, target.Type())
+	}
+	return nil
+}
+
+func decodeTime(value string, layout string, target reflect.Value) error {
+	if value == "" {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	layouts := timeLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	} else if days, err := strconv.ParseFloat(value, 64); err == nil {
+		parsed := serialEpoch.Add(time.Duration(days * float64(24*time.Hour))).Round(time.Millisecond)
+		target.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+	var err error
+	for _, layout := range layouts {
+		var parsed time.Time
+		parsed, err = time.Parse(layout, value)
+		if err == nil {
+			target.Set(reflect.ValueOf(parsed))
+			return nil
+		}
+	}
+	return err
+}
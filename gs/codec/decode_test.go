@@ -0,0 +1,139 @@
+package codec
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type order struct {
+	Id      int       `sheet:"Order Id"`
+	Amount  float64   `sheet:"Amount"`
+	Paid    bool      `sheet:"Paid"`
+	Date    time.Time `sheet:"Date,layout=2006-01-02"`
+	Note    *string   `sheet:"Note"`
+	Count   uint8
+	Ignored string `sheet:"-"`
+	hidden  string
+}
+
+func Test_Decode(t *testing.T) {
+	records := [][]string{
+		{"Order Id", "Amount", "Paid", "Date", "Note", "Count", "Extra"},
+		{"1", "12.5", "TRUE", "2024-01-31", "first", "3", "x"},
+		{"2", "", "false", "", "", ""},
+	}
+	note := "first"
+	expected := []order{
+		{Id: 1, Amount: 12.5, Paid: true, Date: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), Note: &note, Count: 3},
+		{Id: 2},
+	}
+
+	actual := make([]order, 0)
+	err := Decode(records, &actual)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v but found %+v", expected, actual)
+	}
+}
+
+func Test_Decode_Pointers(t *testing.T) {
+	records := [][]string{{"Order Id"}, {"7"}}
+
+	actual := make([]*order, 0)
+	err := Decode(records, &actual)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if len(actual) != 1 || actual[0].Id != 7 {
+		t.Errorf("expected one order with id 7 but found %+v", actual)
+	}
+}
+
+func Test_Decode_CellError(t *testing.T) {
+	records := [][]string{
+		{"Order Id", "Amount"},
+		{"1", "12.5"},
+		{"2", "1,234.50 €"},
+	}
+
+	actual := make([]order, 0)
+	err := Decode(records, &actual)
+
+	cellError := &CellError{}
+	if !errors.As(err, &cellError) {
+		t.Fatalf("expected CellError but found %+v", err)
+	}
+	if cellError.Row != 3 || cellError.Column != 2 || cellError.Header != "Amount" || cellError.Value != "1,234.50 €" {
+		t.Errorf("unexpected cell error %+v", cellError)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected cause '%v' but found '%v'", strconv.ErrSyntax, err)
+	}
+	expectedMessage := "cannot decode '1,234.50 €' in cell B3 (column 'Amount'): strconv.ParseFloat: parsing \"1,234.50 €\": invalid syntax"
+	if err.Error() != expectedMessage {
+		t.Errorf("expected '%s' but found '%s'", expectedMessage, err.Error())
+	}
+}
+
+func Test_Decode_Invalid_Target(t *testing.T) {
+	tests := []any{
+		[]order{},
+		&[]int{},
+		nil,
+		&[]struct{ Values []string }{},
+		&[]struct {
+			A string `sheet:"Name"`
+			B string `sheet:"Name"`
+		}{},
+	}
+	for _, test := range tests {
+		if err := Decode([][]string{{"Name"}}, test); err == nil {
+			t.Errorf("expected error for %T", test)
+		}
+	}
+}
+
+func Test_Decode_Empty(t *testing.T) {
+	actual := []order{{Id: 1}}
+	err := Decode([][]string{}, &actual)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if len(actual) != 0 {
+		t.Errorf("expected no orders but found %+v", actual)
+	}
+}
+
+func Test_Decode_Time(t *testing.T) {
+	type event struct {
+		At time.Time
+	}
+	tests := map[string]time.Time{
+		"2024-01-31T10:30:00Z": time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC),
+		"2024-01-31 10:30:00":  time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC),
+		"2024-01-31":           time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		"45322":                time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		"45322.4375":           time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC),
+		"0":                    time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC),
+	}
+	for value, expected := range tests {
+		actual := make([]event, 0)
+		err := Decode([][]string{{"At"}, {value}}, &actual)
+		if err != nil {
+			t.Fatalf("found error %+v for '%s'", err, value)
+		}
+		if !actual[0].At.Equal(expected) {
+			t.Errorf("expected %v but found %v for '%s'", expected, actual[0].At, value)
+		}
+	}
+
+	err := Decode([][]string{{"At"}, {"1/31/2024"}}, &[]event{})
+	if err == nil {
+		t.Errorf("expected error for a formatted date without layout")
+	}
+}
@@ -0,0 +1,114 @@
+package codec
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Header returns the column names of a struct in field order.
+// src may be a struct, a pointer to a struct or a slice of them.
+func Header(src any) ([]string, error) {
+	structType, err := structType(reflect.TypeOf(src))
+	if err != nil {
+		return nil, err
+	}
+	fields, err := fieldsOf(structType)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0, len(fields))
+	for _, field := range fields {
+		result = append(result, field.name)
+	}
+	return result, nil
+}
+
+// Encode converts structs into records ordered by the given header.
+// src has to be a slice of structs (or pointers to structs).
+// Columns of the header without field are left empty.
+// An error is returned if a field has no column in the header.
+func Encode(src any, header []string) ([][]string, error) {
+	source := reflect.ValueOf(src)
+	if source.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("%w %T, expected a slice", ErrUnsupportedType, src)
+	}
+	structType, err := structType(source.Type())
+	if err != nil {
+		return nil, err
+	}
+	fields, err := fieldsOf(structType)
+	if err != nil {
+		return nil, err
+	}
+	columns := columnsOf(header, fields)
+	for i, column := range columns {
+		if column < 0 {
+			return nil, fmt.Errorf("column '%s' of field %s is missing in header %v", fields[i].name, structType.Field(fields[i].index[0]).Name, header)
+		}
+	}
+
+	result := make([][]string, 0, source.Len())
+	for i := 0; i < source.Len(); i++ {
+		element := source.Index(i)
+		for element.Kind() == reflect.Pointer {
+			element = element.Elem()
+		}
+		record := make([]string, len(header))
+		if element.IsValid() {
+			for j, field := range fields {
+				value, err := encodeValue(element.FieldByIndex(field.index), field.layout)
+				if err != nil {
+					return nil, fmt.Errorf("cannot encode field %s of element %d: %w", field.name, i, err)
+				}
+				record[columns[j]] = value
+			}
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
+
+func encodeValue(value reflect.Value, layout string) (string, error) {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "", nil
+		}
+		return encodeValue(value.Elem(), layout)
+	}
+
+	if value.Type() == timeType {
+		timestamp := value.Interface().(time.Time)
+		if timestamp.IsZero() {
+			return "", nil
+		}
+		if layout == "" {
+			layout = DefaultTimeLayout
+		}
+		return timestamp.Format(layout), nil
+	}
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		if value.Bool() {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("%w %s", ErrUnsupportedType, value.Type())
+	}
+}
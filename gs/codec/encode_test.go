@@ -0,0 +1,76 @@
+package codec
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_Header(t *testing.T) {
+	expected := []string{"Order Id", "Amount", "Paid", "Date", "Note", "Count"}
+
+	for _, src := range []any{order{}, &order{}, []order{}, []*order{}} {
+		actual, err := Header(src)
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected %v but found %v", expected, actual)
+		}
+	}
+}
+
+func Test_Encode(t *testing.T) {
+	note := "first"
+	orders := []*order{
+		{Id: 1, Amount: 12.5, Paid: true, Date: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), Note: &note, Count: 3},
+		{Id: 2},
+		nil,
+	}
+	header := []string{"Extra", "Note", "Date", "Paid", "Amount", "Order Id", "Count"}
+	expected := [][]string{
+		{"", "first", "2024-01-31", "TRUE", "12.5", "1", "3"},
+		{"", "", "", "FALSE", "0", "2", "0"},
+		{"", "", "", "", "", "", ""},
+	}
+
+	actual, err := Encode(orders, header)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+}
+
+func Test_Encode_Roundtrip(t *testing.T) {
+	type event struct {
+		Name string
+		At   time.Time
+	}
+	events := []event{{Name: "launch", At: time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)}}
+	header, err := Header(events)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	records, err := Encode(events, header)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	actual := make([]event, 0)
+	err = Decode(append([][]string{header}, records...), &actual)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if !reflect.DeepEqual(events, actual) {
+		t.Errorf("expected %+v but found %+v", events, actual)
+	}
+}
+
+func Test_Encode_Missing_Column(t *testing.T) {
+	_, err := Encode([]order{{Id: 1}}, []string{"Order Id"})
+	if err == nil {
+		t.Error("expected error for fields without column")
+	}
+}
@@ -0,0 +1,240 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// csvExtension is appended to the sheet names to form the file names of FS
+const csvExtension = ".csv"
+
+// FS provides read-only access to a spreadsheet as a file system.
+// The spreadsheet is the root directory "." and each sheet is a csv file
+// named after the sheet, e.g. "Data.csv". Sheets whose name is not a valid
+// file name, e.g. a name containing "/", are not listed.
+//
+// The sheets are listed as they were when the spreadsheet was opened or last refreshed.
+// The values are read when a file is read. File sizes are reported as 0,
+// since they are unknown until the values are read.
+//
+// FS implements fs.FS, fs.ReadDirFS and fs.StatFS, so it can be used with
+// fs.WalkDir, fs.ReadFile or testing/fstest.
+type FS struct {
+	ctx         context.Context
+	spreadSheet *SpreadSheet
+}
+
+// OpenFS opens the spreadsheet read-only as file system.
+// The context is used for all requests made through the file system.
+// If the spreadsheet does not exist or is not shared with the account, the error
+// matches ErrNotExist or ErrPermission.
+//
+// The credentials are ignored if a http client, a token source or default credentials
+// are provided as option.
+func OpenFS(ctx context.Context, spreadSheetId string, clientCredentialsJson []byte, opts ...Option) (*FS, error) {
+	spreadSheet, err := OpenSpreadSheet(ctx, spreadSheetId, O_RDONLY, clientCredentialsJson, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return spreadSheet.FS(ctx), nil
+}
+
+// FS returns a read-only file system of the spreadsheet, see type FS.
+// The context is used for all requests made through the file system.
+// Sheets are listed as of the last Refresh of the spreadsheet.
+func (spreadSheet *SpreadSheet) FS(ctx context.Context) *FS {
+	if ctx == nil {
+		panic("nil context")
+	}
+	return &FS{
+		ctx:         ctx,
+		spreadSheet: spreadSheet,
+	}
+}
+
+// Open opens the csv file of a sheet or the root directory "."
+func (fileSystem *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return &dirFile{info: fileSystem.rootInfo(), entries: fileSystem.entries()}, nil
+	}
+	info, err := fileSystem.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	sheet, err := fileSystem.spreadSheet.OpenSheet(fileSystem.ctx, info.properties.Title, O_RDONLY)
+	if errors.Is(err, ErrNotExist) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &sheetFile{info: info, sheet: sheet.WithContext(fileSystem.ctx)}, nil
+}
+
+// ReadDir lists the csv files of the sheets sorted by name.
+// Only the root directory "." exists.
+func (fileSystem *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if name != "." {
+		if _, err := fileSystem.lookup("readdir", name); err != nil {
+			return nil, err
+		}
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	return fileSystem.entries(), nil
+}
+
+// Stat returns the file info of the csv file of a sheet or the root directory.
+// The Sys method of the file info of a sheet returns its SheetProperties.
+func (fileSystem *FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return fileSystem.rootInfo(), nil
+	}
+	return fileSystem.lookup("stat", name)
+}
+
+// Returns the spreadsheet of the file system
+func (fileSystem *FS) SpreadSheet() *SpreadSheet {
+	return fileSystem.spreadSheet
+}
+
+func (fileSystem *FS) lookup(op string, name string) (*fileInfo, error) {
+	for _, properties := range fileSystem.spreadSheet.Sheets() {
+		if validFileName(properties.Title) && properties.Title+csvExtension == name {
+			return &fileInfo{properties: properties}, nil
+		}
+	}
+	return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+func (fileSystem *FS) entries() []fs.DirEntry {
+	result := make([]fs.DirEntry, 0)
+	for _, properties := range fileSystem.spreadSheet.Sheets() {
+		if validFileName(properties.Title) {
+			result = append(result, fs.FileInfoToDirEntry(&fileInfo{properties: properties}))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name() < result[j].Name()
+	})
+	return result
+}
+
+func (fileSystem *FS) rootInfo() *fileInfo {
+	return &fileInfo{dir: true}
+}
+
+// validFileName reports whether the sheet name can be used as name of a file within the root directory
+func validFileName(sheetName string) bool {
+	name := sheetName + csvExtension
+	return fs.ValidPath(name) && !strings.Contains(name, "/")
+}
+
+// fileInfo describes the root directory or the csv file of a sheet
+type fileInfo struct {
+	dir        bool
+	properties SheetProperties
+}
+
+func (info *fileInfo) Name() string {
+	if info.dir {
+		return "."
+	}
+	return info.properties.Title + csvExtension
+}
+
+func (info *fileInfo) Size() int64 {
+	return 0
+}
+
+func (info *fileInfo) Mode() fs.FileMode {
+	if info.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (info *fileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (info *fileInfo) IsDir() bool {
+	return info.dir
+}
+
+func (info *fileInfo) Sys() any {
+	if info.dir {
+		return nil
+	}
+	return info.properties
+}
+
+// sheetFile reads the values of a sheet as csv data
+type sheetFile struct {
+	info   *fileInfo
+	sheet  *Sheet
+	closed bool
+}
+
+func (file *sheetFile) Stat() (fs.FileInfo, error) {
+	return file.info, nil
+}
+
+func (file *sheetFile) Read(p []byte) (int, error) {
+	if file.closed {
+		return 0, &fs.PathError{Op: "read", Path: file.info.Name(), Err: fs.ErrClosed}
+	}
+	return file.sheet.Read(p)
+}
+
+func (file *sheetFile) Close() error {
+	if file.closed {
+		return &fs.PathError{Op: "close", Path: file.info.Name(), Err: fs.ErrClosed}
+	}
+	file.closed = true
+	return nil
+}
+
+// dirFile lists the entries of the root directory
+type dirFile struct {
+	info    *fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (dir *dirFile) Stat() (fs.FileInfo, error) {
+	return dir.info, nil
+}
+
+func (dir *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: dir.info.Name(), Err: errors.New("is a directory")}
+}
+
+func (dir *dirFile) Close() error {
+	return nil
+}
+
+func (dir *dirFile) ReadDir(count int) ([]fs.DirEntry, error) {
+	remaining := dir.entries[dir.offset:]
+	if count > 0 && len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if count > 0 && count < len(remaining) {
+		remaining = remaining[:count]
+	}
+	dir.offset += len(remaining)
+	return remaining, nil
+}
@@ -0,0 +1,110 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jo-hoe/google-sheets/gs/gstest"
+)
+
+func Test_FS(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	server.AddSheet("spreadSheetId", "Data", [][]string{{"a", "b"}, {"1", "2"}})
+	server.AddSheet("spreadSheetId", "Archive", [][]string{{"x,y"}})
+	server.AddSheet("spreadSheetId", "2024/25", [][]string{{"hidden"}})
+
+	fileSystem, err := OpenFS(context.Background(), "spreadSheetId", nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	err = fstest.TestFS(fileSystem, "Archive.csv", "Data.csv", "Sheet1.csv")
+	if err != nil {
+		t.Errorf("found error %+v", err)
+	}
+
+	data, err := fs.ReadFile(fileSystem, "Data.csv")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, "a,b\n1,2\n", string(data))
+	data, err = fs.ReadFile(fileSystem, "Archive.csv")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, "\"x,y\"\n", string(data))
+
+	names := make([]string, 0)
+	err = fs.WalkDir(fileSystem, ".", func(path string, entry fs.DirEntry, err error) error {
+		names = append(names, path)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{".", "Archive.csv", "Data.csv", "Sheet1.csv"}, names)
+
+	info, err := fs.Stat(fileSystem, "Data.csv")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, "Data", info.Sys().(SheetProperties).Title)
+}
+
+func Test_FS_Errors(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+
+	fileSystem, err := OpenFS(context.Background(), "spreadSheetId", nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected error
+	}{
+		{name: "missing sheet", path: "Data.csv", expected: fs.ErrNotExist},
+		{name: "missing extension", path: "Sheet1", expected: fs.ErrNotExist},
+		{name: "sub directory", path: "Sheet1.csv/Data.csv", expected: fs.ErrNotExist},
+		{name: "invalid path", path: "./Sheet1.csv", expected: fs.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fileSystem.Open(tt.path)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, found %v", tt.expected, err)
+			}
+			pathError := &fs.PathError{}
+			if !errors.As(err, &pathError) {
+				t.Errorf("expected *fs.PathError, found %T", err)
+			}
+		})
+	}
+
+	// removed sheets are listed until the spreadsheet is refreshed, but cannot be opened
+	server.AddSheet("spreadSheetId", "Data", [][]string{{"a"}})
+	err = RemoveByName(context.Background(), "spreadSheetId", "Sheet1", nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if _, err = fs.Stat(fileSystem, "Sheet1.csv"); err != nil {
+		t.Errorf("found error %+v", err)
+	}
+	if _, err = fileSystem.Open("Sheet1.csv"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected %v, found %v", fs.ErrNotExist, err)
+	}
+	if err = fileSystem.SpreadSheet().Refresh(context.Background()); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if _, err = fs.Stat(fileSystem, "Sheet1.csv"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected %v, found %v", fs.ErrNotExist, err)
+	}
+}
@@ -3,13 +3,17 @@ package gs
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"syscall"
 
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/gs/codec"
 	"github.com/jo-hoe/google-sheets/gs/reader"
 	"github.com/jo-hoe/google-sheets/gs/writer"
 	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
 	"github.com/jo-hoe/google-sheets/internal/client"
+	"golang.org/x/oauth2"
 )
 
 const (
@@ -23,18 +27,142 @@ const (
 )
 
 var (
-	ErrInvalid  = errors.New("invalid argument")     // "invalid argument"
-	ErrExist    = errors.New("sheet already exists") // "file already exists"
-	ErrNotExist = errors.New("sheet does not exist") // "file does not exist"
+	ErrInvalid    = errors.New("invalid argument")     // "invalid argument"
+	ErrExist      = errors.New("sheet already exists") // "file already exists"
+	ErrNotExist   = apiwrapper.ErrNotExist             // "file does not exist"
+	ErrPermission = apiwrapper.ErrPermission           // "permission denied"
+	ErrNotEmpty   = errors.New("sheet is not empty")   // "directory not empty"
 )
 
+// APIError is returned if the Google Sheets API responds with an error.
+// It carries the http status, Google's error payload, and the request url.
+//
+// Use errors.As to access the details, or errors.Is to compare it:
+// a 404 response matches ErrNotExist and a 403 response matches ErrPermission.
+type APIError = apiwrapper.APIError
+
+// CellError is returned by ReadAll if a cell cannot be converted into the type
+// of its struct field. Row and Column refer to the position in the sheet.
+type CellError = codec.CellError
+
+// Rows iterates over the rows of a sheet, see Sheet.Rows
+type Rows = reader.Rows
+
 // Remove removes the sheet in a given spreadspeed.
-func Remove(ctx context.Context, spreadSheetId string, sheetId int32, clientCredentialsJson []byte) error {
-	client, err := createClient(ctx, O_RDWR, clientCredentialsJson)
+// If no sheet has the id, the error matches ErrNotExist.
+func Remove(ctx context.Context, spreadSheetId string, sheetId int32, clientCredentialsJson []byte, opts ...Option) error {
+	options := newOptions(opts)
+	client, err := resolveClient(ctx, O_RDWR, clientCredentialsJson, options)
 	if err != nil {
 		return err
 	}
-	return removeSheetWithClient(spreadSheetId, sheetId, client)
+	return removeSheetWithClient(ctx, spreadSheetId, sheetId, client, opts...)
+}
+
+// RemoveByName removes the sheet with the given name from a spreadsheet.
+// If the sheet does not exist, the error matches ErrNotExist.
+// The last sheet of a spreadsheet cannot be removed (ErrInvalid). A sheet which
+// contains values is only removed with WithForce, otherwise the error matches ErrNotEmpty.
+//
+// The credentials are ignored if a http client, a token source or default credentials
+// are provided as option.
+func RemoveByName(ctx context.Context, spreadSheetId string, sheetName string, clientCredentialsJson []byte, opts ...Option) error {
+	options := newOptions(opts)
+	client, err := resolveClient(ctx, O_RDWR, clientCredentialsJson, options)
+	if err != nil {
+		return err
+	}
+	wrapper := apiwrapper.NewSheetsApiWrapper(client, options.wrapperOptions()...)
+
+	metadata, err := wrapper.GetSpreadSheet(ctx, spreadSheetId)
+	if err != nil {
+		return err
+	}
+	var sheetId int32 = -1
+	for _, properties := range metadata.Sheets {
+		if properties.Title == sheetName {
+			sheetId = properties.Id
+		}
+	}
+	if sheetId < 0 {
+		return fmt.Errorf("%w: sheet '%s'", ErrNotExist, sheetName)
+	}
+	if len(metadata.Sheets) == 1 {
+		return fmt.Errorf("%w: '%s' is the last sheet of the spreadsheet", ErrInvalid, sheetName)
+	}
+	if !options.force {
+		cell, ok, err := wrapper.FirstValue(ctx, spreadSheetId, sheetName)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return fmt.Errorf("%w: '%s' contains a value in %s", ErrNotEmpty, sheetName, cell.String())
+		}
+	}
+	return wrapper.DeleteSheet(ctx, spreadSheetId, sheetId)
+}
+
+// Rename changes the name of a sheet, similar to os.Rename.
+// If no sheet has the old name, the error matches ErrNotExist. In contrast to
+// os.Rename an existing sheet with the new name is not replaced, ErrExist is
+// returned instead. Sheets opened with the old name can no longer be read or written.
+//
+// The credentials are ignored if a http client, a token source or default credentials
+// are provided as option.
+func Rename(ctx context.Context, spreadSheetId string, oldName string, newName string, clientCredentialsJson []byte, opts ...Option) error {
+	if newName == "" {
+		return fmt.Errorf("%w: empty sheet name", ErrInvalid)
+	}
+	options := newOptions(opts)
+	client, err := resolveClient(ctx, O_RDWR, clientCredentialsJson, options)
+	if err != nil {
+		return err
+	}
+	wrapper := apiwrapper.NewSheetsApiWrapper(client, options.wrapperOptions()...)
+
+	metadata, err := wrapper.GetSpreadSheet(ctx, spreadSheetId)
+	if err != nil {
+		return err
+	}
+	var sheetId int32 = -1
+	for _, properties := range metadata.Sheets {
+		switch properties.Title {
+		case oldName:
+			sheetId = properties.Id
+		case newName:
+			return fmt.Errorf("%w: '%s'", ErrExist, newName)
+		}
+	}
+	if sheetId < 0 {
+		return fmt.Errorf("%w: sheet '%s'", ErrNotExist, oldName)
+	}
+	return wrapper.RenameSheet(ctx, spreadSheetId, sheetId, newName)
+}
+
+// Copy copies a sheet including its values and formatting and opens the copy.
+// The destination may be the same spreadsheet or another one the account can
+// write to. The copy is added as last sheet of the destination spreadsheet
+// unless WithIndex is given. If the source sheet does not exist, the error matches ErrNotExist. If the
+// destination already contains a sheet with the name, it matches ErrExist.
+//
+// The credentials are ignored if a http client, a token source or default credentials
+// are provided as option.
+func Copy(ctx context.Context, spreadSheetId string, sheetName string, dstSpreadSheetId string, dstSheetName string, clientCredentialsJson []byte, opts ...Option) (*Sheet, error) {
+	options := newOptions(opts)
+	client, err := resolveClient(ctx, O_RDWR, clientCredentialsJson, options)
+	if err != nil {
+		return nil, err
+	}
+	wrapper := apiwrapper.NewSheetsApiWrapper(client, options.wrapperOptions()...)
+
+	sheetId, err := wrapper.GetSheetId(ctx, spreadSheetId, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	if sheetId < 0 {
+		return nil, fmt.Errorf("%w: sheet '%s'", ErrNotExist, sheetName)
+	}
+	return copySheet(ctx, client, opts, spreadSheetId, sheetId, dstSpreadSheetId, dstSheetName)
 }
 
 // OpenSheet is the generalized open call. It opens the sheet with specified flag (O_RDONLY etc.).
@@ -43,29 +171,70 @@ func Remove(ctx context.Context, spreadSheetId string, sheetId int32, clientCred
 //
 // Can also be used to check if a given file exists.
 // To do so analysis the returned error like so errors.Is(err, gs.ErrExist).
-func OpenSheet(ctx context.Context, spreadSheetId string, sheetName string, flag int, clientCredentialsJson []byte) (*Sheet, error) {
-	client, err := createClient(ctx, flag, clientCredentialsJson)
+//
+// The credentials are ignored if a http client, a token source or default credentials
+// are provided as option.
+func OpenSheet(ctx context.Context, spreadSheetId string, sheetName string, flag int, clientCredentialsJson []byte, opts ...Option) (*Sheet, error) {
+	options := newOptions(opts)
+	client, err := resolveClient(ctx, flag, clientCredentialsJson, options)
 	if err != nil {
 		return nil, err
 	}
-	return openSheetWithClient(spreadSheetId, sheetName, flag, client)
+	return openSheetWithClient(ctx, spreadSheetId, sheetName, flag, client, opts...)
+}
+
+// OpenSheetWithClient works like OpenSheet but uses a caller supplied http client.
+// The client is expected to handle authentication, e.g. a client created with
+// oauth2.NewClient or a client which is routed through a proxy.
+func OpenSheetWithClient(ctx context.Context, spreadSheetId string, sheetName string, flag int, httpClient *http.Client, opts ...Option) (*Sheet, error) {
+	return OpenSheet(ctx, spreadSheetId, sheetName, flag, nil, append(opts, WithHTTPClient(httpClient))...)
+}
+
+// NewUserClient creates a http client which accesses the sheets as the user who authorizes it,
+// to be used with OpenSheetWithClient or WithHTTPClient.
+// The client secret is the JSON file of an OAuth client of type "Desktop app".
+// The flag (O_RDONLY or O_RDWR) determines the requested access.
+//
+// The user is asked to open the authorization url, which is passed to openURL or printed
+// to stderr if openURL is nil. After access is granted, the browser is redirected to a
+// local listener on 127.0.0.1. The authorization code is protected by PKCE.
+// The token is stored in tokenCacheFile with permissions 0600 and reused on
+// subsequent runs, so the user is only asked once. An empty name disables the cache.
+// A cached token which was granted for O_RDONLY is not reused for O_RDWR, the user is asked again.
+func NewUserClient(ctx context.Context, clientSecretJson []byte, flag int, tokenCacheFile string, openURL func(authURL string) error) (*http.Client, error) {
+	return client.NewUserClient(ctx, clientSecretJson, scopes(flag), tokenCacheFile, openURL)
 }
 
-func removeSheetWithClient(spreadSheetId string, sheetId int32, client *http.Client) error {
-	wrapper := apiwrapper.NewSheetsApiWrapper(client)
+func removeSheetWithClient(ctx context.Context, spreadSheetId string, sheetId int32, client *http.Client, opts ...Option) error {
+	wrapper := apiwrapper.NewSheetsApiWrapper(client, newOptions(opts).wrapperOptions()...)
 
-	return wrapper.DeleteSheet(spreadSheetId, sheetId)
+	return wrapper.DeleteSheet(ctx, spreadSheetId, sheetId)
 }
 
-func openSheetWithClient(spreadSheetId string, sheetName string, flag int, client *http.Client) (*Sheet, error) {
+func openSheetWithClient(ctx context.Context, spreadSheetId string, sheetName string, flag int, client *http.Client, opts ...Option) (*Sheet, error) {
 	if client == nil {
 		return nil, ErrInvalid
 	}
+	options := newOptions(opts)
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	wrapperOptions := options.wrapperOptions()
 
-	wrapper := apiwrapper.NewSheetsApiWrapper(client)
+	wrapper := apiwrapper.NewSheetsApiWrapper(client, wrapperOptions...)
+
+	a1Range := a1.Range{Sheet: sheetName}
+	if options.a1Range != "" {
+		var err error
+		a1Range, err = resolveRange(ctx, wrapper, spreadSheetId, sheetName, options.a1Range)
+		if err != nil {
+			return nil, err
+		}
+		sheetName = a1Range.Sheet
+	}
 
 	// check if file exists
-	id, err := wrapper.GetSheetId(spreadSheetId, sheetName)
+	id, err := wrapper.GetSheetId(ctx, spreadSheetId, sheetName)
 	if err != nil {
 		return nil, err
 	}
@@ -77,8 +246,8 @@ func openSheetWithClient(spreadSheetId string, sheetName string, flag int, clien
 			return nil, ErrExist
 		}
 		if hasFlag(flag, O_TRUNC) {
-			// if file exists and content should be truncated -> clear sheet
-			err = wrapper.ClearSheet(spreadSheetId, sheetName)
+			// if file exists and content should be truncated -> clear sheet or range
+			err = wrapper.ClearRange(ctx, spreadSheetId, a1Range)
 			if err != nil {
 				return nil, err
 			}
@@ -86,7 +255,7 @@ func openSheetWithClient(spreadSheetId string, sheetName string, flag int, clien
 	} else {
 		if hasFlag(flag, O_CREATE) {
 			// create new with an id = current timestamp
-			id, err = wrapper.CreateSheet(spreadSheetId, sheetName)
+			id, err = wrapper.CreateSheet(ctx, spreadSheetId, sheetName)
 			if err != nil {
 				return nil, err
 			}
@@ -95,39 +264,196 @@ func openSheetWithClient(spreadSheetId string, sheetName string, flag int, clien
 		}
 	}
 
-	reader, err := reader.NewSheetReader(client, spreadSheetId, sheetName)
+	reader, err := reader.NewRangeReader(client, spreadSheetId, a1Range, wrapperOptions...)
 	if err != nil {
 		return nil, err
 	}
+	reader.SetPageSize(options.pageSize)
 
-	writer, err := writer.NewSheetWriter(client, spreadSheetId, sheetName)
+	writer, err := writer.NewRangeWriter(client, spreadSheetId, a1Range, wrapperOptions...)
 	if err != nil {
 		return nil, err
 	}
+	writer.SetBufferSize(options.writeBufferSize)
+	if options.overwriteAnchor != "" {
+		err = writer.OverwriteAt(options.overwriteAnchor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+	}
+	if options.inputOption != "" {
+		err = writer.SetInputOption(options.inputOption)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+	}
 
 	return &Sheet{
 		id:            id,
 		sheetName:     sheetName,
 		spreadSheetId: spreadSheetId,
+		a1Range:       a1Range,
+		client:        client,
+		opts:          opts,
+		wrapper:       wrapper,
 		reader:        reader,
 		writer:        writer,
 	}, nil
 }
 
+// copySheet copies a sheet into the destination spreadsheet, which may be the
+// spreadsheet of the sheet, and opens the copy. A range of the options only
+// applies to the source sheet, the copy is opened as a whole.
+func copySheet(ctx context.Context, client *http.Client, opts []Option, spreadSheetId string, sheetId int32, dstSpreadSheetId string, dstSheetName string) (*Sheet, error) {
+	if dstSheetName == "" {
+		return nil, fmt.Errorf("%w: empty sheet name", ErrInvalid)
+	}
+	options := newOptions(opts)
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	wrapper := apiwrapper.NewSheetsApiWrapper(client, options.wrapperOptions()...)
+
+	destination, err := wrapper.GetSpreadSheet(ctx, dstSpreadSheetId)
+	if err != nil {
+		return nil, err
+	}
+	for _, properties := range destination.Sheets {
+		if properties.Title == dstSheetName {
+			return nil, fmt.Errorf("%w: '%s'", ErrExist, dstSheetName)
+		}
+	}
+	index := len(destination.Sheets)
+	if options.index != nil && *options.index < index {
+		index = *options.index
+	}
+
+	if dstSpreadSheetId == spreadSheetId {
+		_, err = wrapper.DuplicateSheet(ctx, spreadSheetId, sheetId, dstSheetName, index)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		copied, err := wrapper.CopySheetTo(ctx, spreadSheetId, sheetId, dstSpreadSheetId)
+		if err != nil {
+			return nil, err
+		}
+		err = placeCopy(ctx, wrapper, dstSpreadSheetId, copied, dstSheetName, index)
+		if err != nil {
+			// do not leave the copy behind with the name chosen by the API
+			if deleteErr := wrapper.DeleteSheet(ctx, dstSpreadSheetId, copied.Id); deleteErr != nil {
+				return nil, fmt.Errorf("%w, the copy '%s' could not be deleted: %v", err, copied.Title, deleteErr)
+			}
+			return nil, err
+		}
+	}
+
+	return openSheetWithClient(ctx, dstSpreadSheetId, dstSheetName, O_RDWR, client, append(opts, WithRange(""))...)
+}
+
+// placeCopy renames a sheet copied into another spreadsheet, which is added as last
+// sheet, and moves it to the index
+func placeCopy(ctx context.Context, wrapper *apiwrapper.SheetsApiWrapper, spreadSheetId string, copied apiwrapper.SheetProperties, title string, index int) error {
+	if copied.Title != title {
+		err := wrapper.RenameSheet(ctx, spreadSheetId, copied.Id, title)
+		if err != nil {
+			return err
+		}
+	}
+	if index < copied.Index {
+		return wrapper.MoveSheet(ctx, spreadSheetId, copied.Id, index)
+	}
+	return nil
+}
+
+// resolveRange parses the range set by WithRange. Cells without sheet name
+// refer to the opened sheet. A name without cells is looked up as named range first.
+func resolveRange(ctx context.Context, wrapper *apiwrapper.SheetsApiWrapper, spreadSheetId string, sheetName string, value string) (a1.Range, error) {
+	result, err := a1.ParseRange(value)
+	if err != nil {
+		return a1.Range{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if result.IsWholeSheet() {
+		namedRange, err := wrapper.GetNamedRange(ctx, spreadSheetId, result.Sheet)
+		if err == nil {
+			result = namedRange
+		} else if !errors.Is(err, ErrNotExist) {
+			return a1.Range{}, err
+		}
+	}
+
+	switch {
+	case result.Sheet == "" && sheetName == "":
+		return a1.Range{}, fmt.Errorf("%w: range '%s' does not name a sheet", ErrInvalid, value)
+	case result.Sheet == "":
+		result.Sheet = sheetName
+	case sheetName != "" && result.Sheet != sheetName:
+		return a1.Range{}, fmt.Errorf("%w: range '%s' is not part of sheet '%s'", ErrInvalid, value, sheetName)
+	}
+	return result, nil
+}
+
 func hasFlag(flags int, flag int) bool {
 	return flags&flag != 0
 }
 
-func createClient(ctx context.Context, flag int, clientCredentialsJson []byte) (*http.Client, error) {
-	scope := client.ReadOnlyScopes
-	
-	if hasFlag(flag, O_RDWR) {
-		scope = client.ReadWriteScopes
+// resolveClient returns the http client configured in the options.
+// If none is configured, a client is created from the service account credentials.
+func resolveClient(ctx context.Context, flag int, clientCredentialsJson []byte, options *options) (*http.Client, error) {
+	if options.apiKey != "" {
+		if options.subject != "" {
+			return nil, fmt.Errorf("%w: a subject cannot be combined with an API key", ErrInvalid)
+		}
+		if hasFlag(flag, O_RDWR) {
+			return nil, fmt.Errorf("%w: an API key only allows read access", ErrPermission)
+		}
+		return client.NewAPIKeyClient(options.httpClient, options.apiKey), nil
 	}
-
-	client, err := client.NewServiceAccountClient(ctx, clientCredentialsJson, scope)
+	if options.impersonate == "" {
+		return authenticatedClient(ctx, scopes(flag), clientCredentialsJson, options)
+	}
+	if options.subject != "" {
+		return nil, fmt.Errorf("%w: a subject cannot be combined with impersonation", ErrInvalid)
+	}
+	base, err := authenticatedClient(ctx, client.CloudPlatformScope, clientCredentialsJson, options)
 	if err != nil {
 		return nil, err
 	}
-	return client, err
+	return client.NewImpersonatedClient(ctx, base, options.iamEndpoint, options.impersonate, options.delegates, scopes(flag))
+}
+
+// authenticatedClient creates a client with the authentication given by the options or the credentials
+func authenticatedClient(ctx context.Context, scopes string, clientCredentialsJson []byte, options *options) (*http.Client, error) {
+	if options.subject != "" && (options.httpClient != nil || options.tokenSource != nil || options.metadataEndpoint != "") {
+		// requests would otherwise run as the identity of the client without notice
+		return nil, fmt.Errorf("%w: a subject only applies to service account keys", ErrInvalid)
+	}
+	if options.httpClient != nil {
+		return options.httpClient, nil
+	}
+	if options.tokenSource != nil {
+		return oauth2.NewClient(ctx, options.tokenSource), nil
+	}
+	if options.metadataEndpoint != "" {
+		httpClient, err := client.NewMetadataClient(ctx, options.metadataEndpoint, scopes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		return httpClient, nil
+	}
+	if options.defaultCredentials {
+		return client.NewDefaultClient(ctx, scopes, options.subject)
+	}
+	if clientCredentialsJson == nil {
+		return nil, ErrInvalid
+	}
+	return client.NewServiceAccountClient(ctx, clientCredentialsJson, scopes, options.subject)
+}
+
+// scopes returns the OAuth scopes required to access sheets with the given flag
+func scopes(flag int) string {
+	if hasFlag(flag, O_RDWR) {
+		return client.ReadWriteScopes
+	}
+	return client.ReadOnlyScopes
 }
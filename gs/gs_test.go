@@ -5,10 +5,18 @@ import (
 	"encoding/csv"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/jo-hoe/google-sheets/gs/gstest"
 	"github.com/jo-hoe/google-sheets/internal/client"
+	"golang.org/x/oauth2"
 )
 
 func Test_hasFlag(t *testing.T) {
@@ -63,7 +71,7 @@ func Test_openSheetWithClient(t *testing.T) {
 	mockClient := client.CreateMockClient(sheetResponse, truncatedSheetResponse)
 
 	// test
-	actual, err := openSheetWithClient(expectedSpreadsheetId, expectedSheetName, O_RDWR|O_TRUNC, mockClient)
+	actual, err := openSheetWithClient(context.Background(), expectedSpreadsheetId, expectedSheetName, O_RDWR|O_TRUNC, mockClient)
 
 	if err != nil {
 		t.Errorf("found error %+v", err)
@@ -103,7 +111,7 @@ func Test_openSheetWithClient_O_CREATE(t *testing.T) {
 	mockClient := client.CreateMockClient(sheetResponse, creationResponse)
 
 	// test
-	actual, err := openSheetWithClient(expectedSpreadsheetId, expectedSheetName, O_CREATE, mockClient)
+	actual, err := openSheetWithClient(context.Background(), expectedSpreadsheetId, expectedSheetName, O_CREATE, mockClient)
 
 	if err != nil {
 		t.Errorf("found error %+v", err)
@@ -137,7 +145,7 @@ func Test_openSheetWithClient_O_EXCL(t *testing.T) {
 	mockClient := client.CreateMockClient(sheetResponse)
 
 	// test
-	actual, err := openSheetWithClient(expectedSpreadsheetId, expectedSheetName, O_CREATE|O_EXCL, mockClient)
+	actual, err := openSheetWithClient(context.Background(), expectedSpreadsheetId, expectedSheetName, O_CREATE|O_EXCL, mockClient)
 
 	if !errors.Is(err, ErrExist) {
 		t.Errorf("expected '%v' but found '%v'", ErrExist, err)
@@ -157,7 +165,7 @@ func Test_openSheetWithClient_Non_Existing_File(t *testing.T) {
 	mockClient := client.CreateMockClient(sheetResponse)
 
 	// test
-	actual, err := openSheetWithClient("spreadSheetId", "sheetName", O_RDONLY, mockClient)
+	actual, err := openSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, mockClient)
 
 	if !errors.Is(err, ErrNotExist) {
 		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
@@ -167,6 +175,242 @@ func Test_openSheetWithClient_Non_Existing_File(t *testing.T) {
 	}
 }
 
+func Test_openSheetWithClient_Fake_Server(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+
+	sheet, err := openSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_CREATE|O_RDWR, server.Client())
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	csvWriter := csv.NewWriter(sheet)
+	err = csvWriter.WriteAll([][]string{{"0", "1"}, {"2", "3"}})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	actual, err := csv.NewReader(sheet).ReadAll()
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"0", "1"}, {"2", "3"}}, actual)
+
+	err = removeSheetWithClient(context.Background(), sheet.SpreadSheetId(), sheet.Id(), server.Client())
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{"Sheet1"}, server.SheetNames("spreadSheetId"))
+}
+
+func Test_OpenSheetWithClient_Endpoint(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a", "b"}})
+
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, http.DefaultClient, WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	actual, err := csv.NewReader(sheet).ReadAll()
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"a", "b"}}, actual)
+}
+
+func Test_OpenSheet_TokenSource(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+
+	sheet, err := OpenSheet(context.Background(), "spreadSheetId", "sheetName", O_RDWR|O_CREATE, nil, WithTokenSource(tokenSource), WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	err = Remove(context.Background(), "spreadSheetId", sheet.Id(), nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{"Sheet1"}, server.SheetNames("spreadSheetId"))
+}
+
+func Test_OpenSheet_Canceled_Context(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := OpenSheetWithClient(ctx, "spreadSheetId", "sheetName", O_RDWR|O_CREATE, server.Client())
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected '%v' but found '%v'", context.Canceled, err)
+	}
+	assertEqual(t, []string{"Sheet1"}, server.SheetNames("spreadSheetId"))
+}
+
+func Test_OpenSheet_WithRetry(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+	server.FailRequests(http.StatusTooManyRequests, 2)
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDWR, server.Client(), WithRetry(policy))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	server.FailRequests(http.StatusTooManyRequests, 1)
+	_, err = sheet.Write([]byte("a,b\n"))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	assertEqual(t, [][]string{{"a", "b"}}, server.Values("spreadSheetId", "sheetName"))
+}
+
+func Test_OpenSheet_Missing_SpreadSheet(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+
+	_, err := OpenSheetWithClient(context.Background(), "unknown", "sheetName", O_RDONLY, server.Client())
+
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+	apiError := &APIError{}
+	if !errors.As(err, &apiError) || apiError.Status != "NOT_FOUND" {
+		t.Errorf("expected api error with status NOT_FOUND but found '%v'", err)
+	}
+}
+
+func Test_OpenSheet_Shared_RateLimiter(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+	limiter := NewRateLimiter(1, 0)
+
+	_, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, server.Client(), WithRateLimiter(limiter))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	// the only read request of the minute is used up by the first sheet
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = OpenSheetWithClient(ctx, "spreadSheetId", "sheetName", O_RDONLY, server.Client(), WithRateLimiter(limiter))
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected '%v' but found '%v'", context.DeadlineExceeded, err)
+	}
+}
+
+func Test_OpenSheet_WithRange(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Data", [][]string{
+		{"id", "name", "note"},
+		{"1", "a", "x"},
+		{"2", "b", "y"},
+		{"3", "c", "z"},
+	})
+
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "", O_RDWR, server.Client(), WithRange("Data!A2:B3"))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if sheet.Name() != "Data" {
+		t.Errorf("expected sheet name 'Data' but found '%s'", sheet.Name())
+	}
+	if sheet.Range().String() != "Data!A2:B3" {
+		t.Errorf("expected range 'Data!A2:B3' but found '%s'", sheet.Range().String())
+	}
+
+	actual, err := csv.NewReader(sheet).ReadAll()
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"1", "a"}, {"2", "b"}}, actual)
+}
+
+func Test_OpenSheet_WithRange_Relative_Truncate(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Data", [][]string{
+		{"a", "b", "c"},
+		{"d", "e", "f"},
+	})
+
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "Data", O_RDWR|O_TRUNC, server.Client(), WithRange("B1:C"))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"a"}, {"d"}}, server.Values("spreadSheetId", "Data"))
+
+	_, err = sheet.Write([]byte("1,2\n"))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"a", "1", "2"}, {"d"}}, server.Values("spreadSheetId", "Data"))
+}
+
+func Test_OpenSheet_WithRange_Named(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Data", [][]string{{"a", "b"}, {"c", "d"}})
+	err := server.AddNamedRange("spreadSheetId", "Column", "Data!B1:B2")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "", O_RDONLY, server.Client(), WithRange("Column"))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	actual, err := csv.NewReader(sheet).ReadAll()
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"b"}, {"d"}}, actual)
+}
+
+func Test_OpenSheet_WithRange_Invalid(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Data", nil)
+
+	tests := map[string]error{
+		"Data!A1:":  ErrInvalid,
+		"Other!A1":  ErrInvalid,
+		"Unknown":   ErrInvalid,
+		"Data!B2:C": nil,
+	}
+	for a1Range, expected := range tests {
+		_, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "Data", O_RDONLY, server.Client(), WithRange(a1Range))
+		if !errors.Is(err, expected) {
+			t.Errorf("expected '%v' for '%s' but found '%v'", expected, a1Range, err)
+		}
+	}
+
+	_, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "", O_RDONLY, server.Client(), WithRange("Unknown"))
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+}
+
+func Test_OpenSheet_Without_Credentials(t *testing.T) {
+	_, err := OpenSheet(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, nil)
+
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
+
 func Test_RemoveSheetWithClient(t *testing.T) {
 	// mock a scenario where the sheet exists already
 	sheetResponse := client.ResponseSummery{
@@ -175,15 +419,15 @@ func Test_RemoveSheetWithClient(t *testing.T) {
 
 	mockClient := client.CreateMockClient(sheetResponse)
 
-	err := removeSheetWithClient("spreadSheetId", 1, mockClient)
+	err := removeSheetWithClient(context.Background(), "spreadSheetId", 1, mockClient)
 
 	if err != nil {
 		t.Errorf("found error '%+v'", err)
 	}
 }
 
-func Test_createClient(t *testing.T) {
-	client, err := createClient(context.Background(), O_CREATE, []byte(`{"type":"service_account"}`))
+func Test_authenticatedClient(t *testing.T) {
+	client, err := authenticatedClient(context.Background(), scopes(O_CREATE), []byte(`{"type":"service_account"}`), newOptions(nil))
 
 	if err != nil {
 		t.Errorf("found error '%+v'", err)
@@ -212,3 +456,285 @@ func assertEqual(t *testing.T, expected any, actual any) {
 		t.Errorf("expected '%+v' but found '%+v'", expected, actual)
 	}
 }
+
+func Test_Rename(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	server.AddSheet("spreadSheetId", "Draft", [][]string{{"a"}})
+
+	err := Rename(context.Background(), "spreadSheetId", "Draft", "Report", nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{"Sheet1", "Report"}, server.SheetNames("spreadSheetId"))
+	assertEqual(t, [][]string{{"a"}}, server.Values("spreadSheetId", "Report"))
+
+	err = Rename(context.Background(), "spreadSheetId", "Draft", "Other", nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+	err = Rename(context.Background(), "spreadSheetId", "Report", "Sheet1", nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrExist, err)
+	}
+	err = Rename(context.Background(), "spreadSheetId", "Report", "", nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
+
+func Test_Copy(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	server.AddSheet("spreadSheetId", "Template", [][]string{{"name", "amount"}})
+	server.AddSpreadSheet("customerId")
+	ctx := context.Background()
+
+	copied, err := Copy(ctx, "spreadSheetId", "Template", "spreadSheetId", "Customer A", nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, "Customer A", copied.Name())
+	assertEqual(t, []string{"Sheet1", "Template", "Customer A"}, server.SheetNames("spreadSheetId"))
+	if _, err = copied.Write([]byte("rent,100\n")); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"name", "amount"}, {"rent", "100"}}, server.Values("spreadSheetId", "Customer A"))
+	assertEqual(t, [][]string{{"name", "amount"}}, server.Values("spreadSheetId", "Template"))
+
+	copied, err = Copy(ctx, "spreadSheetId", "Template", "customerId", "Template", nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, "customerId", copied.SpreadSheetId())
+	assertEqual(t, []string{"Sheet1", "Template"}, server.SheetNames("customerId"))
+	assertEqual(t, [][]string{{"name", "amount"}}, server.Values("customerId", "Template"))
+
+	_, err = Copy(ctx, "spreadSheetId", "Template", "customerId", "Template", nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrExist, err)
+	}
+	_, err = Copy(ctx, "spreadSheetId", "Unknown", "customerId", "Other", nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+	_, err = Copy(ctx, "spreadSheetId", "Template", "unknown", "Other", nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+}
+
+func Test_Copy_Index(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	server.AddSheet("spreadSheetId", "Template", [][]string{{"name", "amount"}})
+	server.AddSpreadSheet("customerId")
+	server.AddSheet("customerId", "Data", nil)
+	ctx := context.Background()
+
+	_, err := Copy(ctx, "spreadSheetId", "Template", "spreadSheetId", "First", nil, WithHTTPClient(server.Client()), WithIndex(0))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{"First", "Sheet1", "Template"}, server.SheetNames("spreadSheetId"))
+
+	_, err = Copy(ctx, "spreadSheetId", "Template", "customerId", "Second", nil, WithHTTPClient(server.Client()), WithIndex(1))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{"Sheet1", "Second", "Data"}, server.SheetNames("customerId"))
+
+	_, err = Copy(ctx, "spreadSheetId", "Template", "customerId", "Other", nil, WithHTTPClient(server.Client()), WithIndex(-1))
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
+
+func Test_Copy_Rename_Failed(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Template", [][]string{{"a"}})
+	server.AddSpreadSheet("customerId")
+	base := server.Client()
+	failed := false
+	// reject the first update of the destination, which renames the copy
+	httpClient := client.NewMockClient(func(request *http.Request) *http.Response {
+		if !failed && strings.HasSuffix(request.URL.Path, "/customerId:batchUpdate") {
+			failed = true
+			return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader("{}"))}
+		}
+		response, err := base.Transport.RoundTrip(request)
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		return response
+	})
+
+	_, err := Copy(context.Background(), "spreadSheetId", "Template", "customerId", "Customer", nil, WithHTTPClient(httpClient))
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	// the copy named by the API is deleted again
+	assertEqual(t, []string{"Sheet1"}, server.SheetNames("customerId"))
+}
+
+func Test_RemoveByName(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	server.AddSheet("spreadSheetId", "Empty", nil)
+	server.AddSheet("spreadSheetId", "Data", [][]string{{}, {"", "a"}})
+	ctx := context.Background()
+
+	if err := RemoveByName(ctx, "spreadSheetId", "Empty", nil, WithHTTPClient(server.Client())); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{"Sheet1", "Data"}, server.SheetNames("spreadSheetId"))
+
+	err := RemoveByName(ctx, "spreadSheetId", "Data", nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrNotEmpty) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotEmpty, err)
+	}
+	if err = RemoveByName(ctx, "spreadSheetId", "Data", nil, WithHTTPClient(server.Client()), WithForce()); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{"Sheet1"}, server.SheetNames("spreadSheetId"))
+
+	err = RemoveByName(ctx, "spreadSheetId", "Data", nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+	err = RemoveByName(ctx, "spreadSheetId", "Sheet1", nil, WithHTTPClient(server.Client()), WithForce())
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
+
+func Test_Remove_NotExist(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	server.AddSheet("spreadSheetId", "Data", nil)
+
+	err := Remove(context.Background(), "spreadSheetId", 999, nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+	apiError := &APIError{}
+	if !errors.As(err, &apiError) {
+		t.Errorf("expected APIError but found '%v'", err)
+	}
+}
+
+func Test_OpenSheet_MetadataEndpoint(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a"}})
+	scopes := make([]string, 0)
+	metadataServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scopes = append(scopes, r.URL.Query().Get("scopes"))
+		fmt.Fprint(w, `{"access_token":"token","expires_in":3599,"token_type":"Bearer"}`)
+	}))
+	defer metadataServer.Close()
+
+	sheet, err := OpenSheet(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, nil,
+		WithMetadataEndpoint(metadataServer.URL), WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	actual, err := csv.NewReader(sheet).ReadAll()
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"a"}}, actual)
+	assertEqual(t, []string{"https://www.googleapis.com/auth/spreadsheets.readonly"}, scopes)
+}
+
+func Test_OpenSheet_DefaultCredentials_Missing(t *testing.T) {
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", filepath.Join(t.TempDir(), "missing.json"))
+
+	_, err := OpenSheet(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, nil, WithDefaultCredentials())
+	if err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func Test_OpenSheet_Impersonation(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a"}})
+	sheetsTransport := server.Client().Transport
+	authorizations := make([]string, 0)
+	// the client of the caller, e.g. with a proxy, carries the requests to the IAM API and the sheets
+	httpClient := client.NewMockClient(func(r *http.Request) *http.Response {
+		if r.URL.Host == "iam.test" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"accessToken":"impersonated","expireTime":"2100-01-01T00:00:00Z"}`)),
+			}
+		}
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		response, err := sheetsTransport.RoundTrip(r)
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		return response
+	})
+
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, httpClient,
+		WithImpersonation("target@project.iam.gserviceaccount.com"), WithIAMEndpoint("http://iam.test/"))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	actual, err := csv.NewReader(sheet).ReadAll()
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"a"}}, actual)
+	if len(authorizations) == 0 {
+		t.Errorf("expected requests to the sheets api")
+	}
+	for _, authorization := range authorizations {
+		assertEqual(t, "Bearer impersonated", authorization)
+	}
+}
+
+func Test_OpenSheet_Subject_With_Impersonation(t *testing.T) {
+	_, err := OpenSheet(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, []byte(`{"type":"service_account"}`),
+		WithSubject("user@example.com"), WithImpersonation("target@project.iam.gserviceaccount.com"))
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
+
+func Test_OpenSheet_Subject_Without_Key(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+
+	tests := map[string]Option{
+		"http client":       WithHTTPClient(server.Client()),
+		"token source":      WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})),
+		"metadata endpoint": WithMetadataEndpoint(server.URL),
+		"api key":           WithAPIKey("key"),
+	}
+	for name, option := range tests {
+		_, err := OpenSheet(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, nil, option, WithSubject("user@example.com"))
+		if !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: expected '%v' but found '%v'", name, ErrInvalid, err)
+		}
+	}
+}
+
+func Test_NewUserClient_Invalid_Secret(t *testing.T) {
+	_, err := NewUserClient(context.Background(), []byte(`{"type":"service_account"}`), O_RDONLY, "", func(string) error {
+		t.Errorf("expected no authorization for an invalid client secret")
+		return nil
+	})
+	if err == nil {
+		t.Errorf("expected error")
+	}
+}
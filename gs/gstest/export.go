@@ -0,0 +1,92 @@
+package gstest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// exportPrefix is the path of the csv export of the Google Visualization API,
+// e.g. /spreadsheets/d/{id}/gviz/tq?tqx=out:csv&sheet={name}
+const exportPrefix = "/spreadsheets/d/"
+
+// SetPublic shares a spreadsheet with anyone who has the link or stops sharing it.
+// Public spreadsheets can be read with an API key and through the csv export.
+// Requests with an API key are denied for spreadsheets which are not public.
+func (server *Server) SetPublic(spreadSheetId string, public bool) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if spreadSheet, ok := server.spreadSheets[spreadSheetId]; ok {
+		spreadSheet.public = public
+	}
+}
+
+// handleExport answers requests of the csv export. Like docs.google.com it answers
+// with a html page if the spreadsheet is not public.
+func (server *Server) handleExport(w http.ResponseWriter, r *http.Request, path string) {
+	spreadSheetId, rest, _ := strings.Cut(strings.TrimPrefix(path, exportPrefix), "/")
+	spreadSheet, ok := server.spreadSheets[spreadSheetId]
+	if !ok || rest != "gviz/tq" {
+		writeHtml(w, http.StatusNotFound, "Sorry, the file you have requested does not exist.")
+		return
+	}
+	if !spreadSheet.public {
+		writeHtml(w, http.StatusOK, "Sign in to continue to Sheets")
+		return
+	}
+	query := r.URL.Query()
+	if query.Get("tqx") != "out:csv" {
+		writeHtml(w, http.StatusBadRequest, "Unsupported output format")
+		return
+	}
+	sheet := spreadSheet.sheets[0]
+	if name := query.Get("sheet"); name != "" {
+		sheet = spreadSheet.sheetByTitle(name)
+	}
+	if sheet == nil {
+		writeHtml(w, http.StatusBadRequest, fmt.Sprintf("Invalid sheet '%s'", query.Get("sheet")))
+		return
+	}
+
+	// the export contains all cells up to the last column with values
+	values := trimValues(copyValues(sheet.values))
+	width := 0
+	for _, row := range values {
+		width = max(width, len(row))
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	csvWriter := csv.NewWriter(w)
+	for _, row := range values {
+		record := make([]string, width)
+		for i, value := range row {
+			record[i] = fmt.Sprint(renderValue(value, renderFormatted))
+		}
+		csvWriter.Write(record)
+	}
+	csvWriter.Flush()
+}
+
+// checkAPIKey denies requests with an API key unless they read a public spreadsheet.
+// It returns false if an error was written.
+func checkAPIKey(w http.ResponseWriter, r *http.Request, spreadSheet *fakeSpreadSheet) bool {
+	if r.URL.Query().Get("key") == "" {
+		return true
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusUnauthorized, "UNAUTHENTICATED", "API keys are not supported by this API. Expected OAuth2 access token or other authentication credentials that assert a principal.")
+		return false
+	}
+	if spreadSheet == nil || !spreadSheet.public {
+		writeError(w, http.StatusForbidden, "PERMISSION_DENIED", "The caller does not have permission")
+		return false
+	}
+	return true
+}
+
+func writeHtml(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	fmt.Fprintf(w, "<!DOCTYPE html><html><body>%s</body></html>", message)
+}
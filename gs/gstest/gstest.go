@@ -0,0 +1,1119 @@
+// Package gstest provides an in-process fake of the Google Sheets v4 API.
+//
+// The fake keeps its spreadsheets in memory and implements the subset of
+// endpoints used by this module, so code built on top of package gs can be
+// tested end to end without access to Google.
+package gstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+)
+
+const apiPrefix = "/v4/spreadsheets/"
+
+const defaultRowCount = 1000
+const defaultColumnCount = 26
+const defaultLocale = "en_US"
+const defaultTimeZone = "Etc/GMT"
+
+// Server is a stateful fake of the Google Sheets v4 API backed by an httptest.Server.
+type Server struct {
+	// URL of the fake in the form http://ipaddr:port with no trailing slash
+	URL string
+
+	server       *httptest.Server
+	mutex        sync.Mutex
+	spreadSheets map[string]*fakeSpreadSheet
+	nextSheetId  int32
+	// number of spreadsheets created through the API
+	created  int
+	failures []int
+}
+
+type fakeSpreadSheet struct {
+	id          string
+	title       string
+	locale      string
+	timeZone    string
+	sheets      []*fakeSheet
+	namedRanges []fakeNamedRange
+	// shared with anyone who has the link
+	public bool
+}
+
+type fakeNamedRange struct {
+	id      string
+	name    string
+	sheetId int32
+	a1Range a1.Range
+}
+
+type fakeSheet struct {
+	id          int32
+	title       string
+	rowCount    int
+	columnCount int
+	hidden      bool
+	// number of frozen rows and columns
+	frozenRows    int
+	frozenColumns int
+	tabColor      *rgbColorJson
+	values        [][]string
+}
+
+// NewServer starts and returns a new fake server.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	server := &Server{
+		spreadSheets: make(map[string]*fakeSpreadSheet),
+		nextSheetId:  1,
+	}
+	server.server = httptest.NewServer(http.HandlerFunc(server.handle))
+	server.URL = server.server.URL
+	return server
+}
+
+// Close shuts down the server and blocks until all outstanding requests have completed.
+func (server *Server) Close() {
+	server.server.Close()
+}
+
+// Client returns a http client which sends all requests to the fake server,
+// regardless of the host the request is addressed to.
+func (server *Server) Client() *http.Client {
+	target, _ := url.Parse(server.URL)
+	return &http.Client{
+		Transport: &rewriteTransport{
+			target: target,
+			base:   server.server.Client().Transport,
+		},
+	}
+}
+
+// AddSpreadSheet creates an empty spreadsheet containing a single sheet named "Sheet1".
+// Existing spreadsheets with the same id are replaced.
+func (server *Server) AddSpreadSheet(spreadSheetId string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.spreadSheets[spreadSheetId] = newFakeSpreadSheet(spreadSheetId, spreadSheetId)
+	server.addSheet(spreadSheetId, "Sheet1", nil, 0)
+}
+
+// AddSheet adds a sheet with the given values to a spreadsheet and returns its id.
+// The values are interpreted as if they were typed into the UI, i.e. "1.5" is a number,
+// "TRUE" a boolean and "=A1" a formula. Prefix a value with "'" to store it as text.
+// The spreadsheet is created if it does not exist yet.
+func (server *Server) AddSheet(spreadSheetId string, sheetName string, values [][]string) int32 {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if _, ok := server.spreadSheets[spreadSheetId]; !ok {
+		server.spreadSheets[spreadSheetId] = newFakeSpreadSheet(spreadSheetId, spreadSheetId)
+	}
+	id := server.nextSheetId
+	server.nextSheetId++
+	server.addSheet(spreadSheetId, sheetName, values, id)
+	return id
+}
+
+// Values returns a copy of the values stored in a sheet as displayed in the UI.
+// Formulas are not evaluated and returned as they were entered.
+// Nil is returned if the sheet does not exist.
+func (server *Server) Values(spreadSheetId string, sheetName string) [][]string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	spreadSheet, ok := server.spreadSheets[spreadSheetId]
+	if !ok {
+		return nil
+	}
+	sheet := spreadSheet.sheetByTitle(sheetName)
+	if sheet == nil {
+		return nil
+	}
+	values := trimValues(sheet.values)
+	result := make([][]string, 0, len(values))
+	for _, row := range values {
+		displayed := make([]string, 0, len(row))
+		for _, value := range row {
+			displayed = append(displayed, displayValue(value))
+		}
+		result = append(result, displayed)
+	}
+	return result
+}
+
+// SheetNames returns the titles of all sheets in a spreadsheet in order.
+func (server *Server) SheetNames(spreadSheetId string) []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	spreadSheet, ok := server.spreadSheets[spreadSheetId]
+	if !ok {
+		return nil
+	}
+	result := make([]string, 0, len(spreadSheet.sheets))
+	for _, sheet := range spreadSheet.sheets {
+		result = append(result, sheet.title)
+	}
+	return result
+}
+
+// AddNamedRange defines a named range (e.g. "Data!B2:C10") in a spreadsheet.
+// The sheet of the range has to exist already.
+func (server *Server) AddNamedRange(spreadSheetId string, name string, a1Range string) error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	spreadSheet, ok := server.spreadSheets[spreadSheetId]
+	if !ok {
+		return fmt.Errorf("spreadsheet '%s' does not exist", spreadSheetId)
+	}
+	parsed, err := a1.ParseRange(a1Range)
+	if err != nil {
+		return err
+	}
+	sheet := spreadSheet.sheetByTitle(parsed.Sheet)
+	if sheet == nil {
+		return fmt.Errorf("sheet '%s' does not exist", parsed.Sheet)
+	}
+	spreadSheet.namedRanges = append(spreadSheet.namedRanges, fakeNamedRange{
+		id:      fmt.Sprintf("namedRange%d", len(spreadSheet.namedRanges)+1),
+		name:    name,
+		sheetId: sheet.id,
+		a1Range: parsed,
+	})
+	return nil
+}
+
+// FailRequests lets the next count requests fail with the given status code,
+// e.g. http.StatusTooManyRequests to simulate exhausted quotas.
+func (server *Server) FailRequests(statusCode int, count int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	for i := 0; i < count; i++ {
+		server.failures = append(server.failures, statusCode)
+	}
+}
+
+func (server *Server) addSheet(spreadSheetId string, sheetName string, values [][]string, id int32) {
+	sheet := &fakeSheet{
+		id:          id,
+		title:       sheetName,
+		rowCount:    defaultRowCount,
+		columnCount: defaultColumnCount,
+	}
+	sheet.setValues(0, 0, values)
+
+	spreadSheet := server.spreadSheets[spreadSheetId]
+	spreadSheet.sheets = append(spreadSheet.sheets, sheet)
+}
+
+func (server *Server) handle(w http.ResponseWriter, r *http.Request) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if len(server.failures) > 0 {
+		statusCode := server.failures[0]
+		server.failures = server.failures[1:]
+		writeError(w, statusCode, statusName(statusCode), http.StatusText(statusCode))
+		return
+	}
+
+	path := r.URL.EscapedPath()
+	if strings.HasPrefix(path, exportPrefix) {
+		server.handleExport(w, r, path)
+		return
+	}
+	if path == strings.TrimSuffix(apiPrefix, "/") && r.Method == http.MethodPost {
+		if checkAPIKey(w, r, nil) {
+			server.handleCreate(w, r)
+		}
+		return
+	}
+	if !strings.HasPrefix(path, apiPrefix) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+		return
+	}
+	path = strings.TrimPrefix(path, apiPrefix)
+
+	spreadSheetId, rangePart, hasValues := strings.Cut(path, "/values/")
+	action := ""
+	if !hasValues {
+		spreadSheetId, action, _ = strings.Cut(spreadSheetId, ":")
+	}
+	spreadSheetId, batchValues := strings.CutSuffix(spreadSheetId, "/values")
+	spreadSheetId, sheetPart, hasSheet := strings.Cut(spreadSheetId, "/sheets/")
+	spreadSheet, ok := server.spreadSheets[spreadSheetId]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+		return
+	}
+	if !checkAPIKey(w, r, spreadSheet) {
+		return
+	}
+
+	if batchValues {
+		if action != "batchUpdate" || r.Method != http.MethodPost {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+			return
+		}
+		server.handleBatchUpdateValues(w, r, spreadSheet)
+		return
+	}
+	if hasSheet {
+		var sheetId int32
+		_, err := fmt.Sscanf(sheetPart, "%d", &sheetId)
+		if err != nil || action != "copyTo" || r.Method != http.MethodPost {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+			return
+		}
+		server.handleCopyTo(w, r, spreadSheet, sheetId)
+		return
+	}
+	if hasValues {
+		for _, suffix := range []string{":append", ":clear"} {
+			if strings.HasSuffix(rangePart, suffix) {
+				rangePart = strings.TrimSuffix(rangePart, suffix)
+				action = strings.TrimPrefix(suffix, ":")
+			}
+		}
+		rangeName, err := url.PathUnescape(rangePart)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", fmt.Sprintf("Unable to parse range: %s", rangePart))
+			return
+		}
+		server.handleValues(w, r, spreadSheet, rangeName, action)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJson(w, spreadSheet.toJson())
+	case action == "batchUpdate" && r.Method == http.MethodPost:
+		server.handleBatchUpdate(w, r, spreadSheet)
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+	}
+}
+
+// handleCreate creates a spreadsheet with a generated id and a first sheet named "Sheet1"
+func (server *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	body := spreadSheetJson{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", fmt.Sprintf("Invalid JSON payload received. %v", err))
+		return
+	}
+	server.created++
+	spreadSheetId := fmt.Sprintf("created-spreadsheet-%d", server.created)
+	title := body.Properties.Title
+	if title == "" {
+		title = "Untitled spreadsheet"
+	}
+
+	spreadSheet := newFakeSpreadSheet(spreadSheetId, title)
+	if body.Properties.Locale != "" {
+		spreadSheet.locale = body.Properties.Locale
+	}
+	if body.Properties.TimeZone != "" {
+		spreadSheet.timeZone = body.Properties.TimeZone
+	}
+	server.spreadSheets[spreadSheetId] = spreadSheet
+	server.addSheet(spreadSheetId, "Sheet1", nil, 0)
+	writeJson(w, spreadSheet.toJson())
+}
+
+// handleCopyTo copies a sheet into another spreadsheet as "Copy of <title>"
+func (server *Server) handleCopyTo(w http.ResponseWriter, r *http.Request, spreadSheet *fakeSpreadSheet, sheetId int32) {
+	body := copySheetToRequestJson{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", fmt.Sprintf("Invalid JSON payload received. %v", err))
+		return
+	}
+	source := spreadSheet.sheetById(sheetId)
+	if source == nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("No grid with id: %d", sheetId))
+		return
+	}
+	destination, ok := server.spreadSheets[body.DestinationSpreadSheetId]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+		return
+	}
+
+	title := "Copy of " + source.title
+	for i := 2; destination.sheetByTitle(title) != nil; i++ {
+		title = fmt.Sprintf("Copy of %s %d", source.title, i)
+	}
+	copied := source.copy(server.nextSheetId, title)
+	server.nextSheetId++
+	destination.sheets = append(destination.sheets, copied)
+	writeJson(w, destination.propertiesJson(copied))
+}
+
+func (server *Server) handleValues(w http.ResponseWriter, r *http.Request, spreadSheet *fakeSpreadSheet, rangeName string, action string) {
+	a1Range, err := a1.ParseRange(rangeName)
+	if err == nil && a1Range.Sheet == "" {
+		// ranges without sheet name refer to the first sheet
+		a1Range.Sheet = spreadSheet.sheets[0].title
+	}
+	sheet := spreadSheet.sheetByTitle(a1Range.Sheet)
+	if err != nil || sheet == nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", fmt.Sprintf("Unable to parse range: %s", rangeName))
+		return
+	}
+	row, column, endRow, endColumn := sheet.bounds(a1Range)
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		renderOption := r.URL.Query().Get("valueRenderOption")
+		if renderOption == "" {
+			renderOption = renderFormatted
+		}
+		if renderOption != renderFormatted && renderOption != renderUnformatted && renderOption != renderFormula {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", fmt.Sprintf("Invalid value at 'value_render_option' (%s)", renderOption))
+			return
+		}
+		responseRange := sheet.gridRange()
+		if !a1Range.IsWholeSheet() {
+			responseRange = sheet.rangeBetween(row, column, endRow, endColumn)
+		}
+		writeJson(w, valueRangeJson{
+			Range:          responseRange,
+			MajorDimension: "ROWS",
+			Values:         renderValues(trimValues(sheet.valuesBetween(row, column, endRow, endColumn)), renderOption),
+		})
+	case action == "append" && r.Method == http.MethodPost:
+		body, ok := readValues(w, r)
+		if !ok {
+			return
+		}
+		// the table is searched within the columns of the range
+		table := trimValues(sheet.valuesBetween(row, column, sheet.rowCount, endColumn))
+		tableRange := ""
+		if len(table) > 0 {
+			tableRange = sheet.rangeOf(row, column, table)
+		}
+		startRow := row + len(table)
+		sheet.setValues(startRow, column, body)
+		updatedRange := sheet.rangeOf(startRow, column, body)
+		writeJson(w, appendResponseJson{
+			SpreadSheetId: spreadSheet.id,
+			TableRange:    tableRange,
+			Updates: updateResponseJson{
+				SpreadSheetId: spreadSheet.id,
+				UpdatedRange:  updatedRange,
+				UpdatedRows:   len(body),
+				UpdatedCells:  countCells(body),
+			},
+		})
+	case action == "" && r.Method == http.MethodPut:
+		body, ok := readValues(w, r)
+		if !ok {
+			return
+		}
+		// a single cell only marks the start of the data
+		if a1Range.Start != a1Range.End {
+			if message := exceedsRange(body, a1Range, row, column, endRow, endColumn); message != "" {
+				writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", message)
+				return
+			}
+		}
+		sheet.setValues(row, column, body)
+		writeJson(w, updateResponseJson{
+			SpreadSheetId: spreadSheet.id,
+			UpdatedRange:  sheet.rangeOf(row, column, body),
+			UpdatedRows:   len(body),
+			UpdatedCells:  countCells(body),
+		})
+	case action == "clear" && r.Method == http.MethodPost:
+		clearedRange := sheet.gridRange()
+		if a1Range.IsWholeSheet() {
+			sheet.values = nil
+		} else {
+			sheet.clearBetween(row, column, endRow, endColumn)
+			clearedRange = sheet.rangeBetween(row, column, endRow, endColumn)
+		}
+		writeJson(w, clearResponseJson{
+			SpreadSheetId: spreadSheet.id,
+			ClearedRange:  clearedRange,
+		})
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Requested entity was not found.")
+	}
+}
+
+// handleBatchUpdateValues writes the values of several ranges. Like the API it
+// applies none of them if one range is invalid.
+func (server *Server) handleBatchUpdateValues(w http.ResponseWriter, r *http.Request, spreadSheet *fakeSpreadSheet) {
+	body := batchUpdateValuesRequestJson{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+	if body.ValueInputOption != inputRaw && body.ValueInputOption != inputUserEntered {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "'valueInputOption' is required but not specified")
+		return
+	}
+
+	type update struct {
+		sheet       *fakeSheet
+		row, column int
+		values      [][]string
+	}
+	updates := make([]update, 0, len(body.Data))
+	for _, data := range body.Data {
+		a1Range, err := a1.ParseRange(data.Range)
+		if err == nil && a1Range.Sheet == "" {
+			a1Range.Sheet = spreadSheet.sheets[0].title
+		}
+		sheet := spreadSheet.sheetByTitle(a1Range.Sheet)
+		if err != nil || sheet == nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", fmt.Sprintf("Unable to parse range: %s", data.Range))
+			return
+		}
+		values := enterValues(data.Values, body.ValueInputOption == inputRaw)
+		row, column, endRow, endColumn := sheet.bounds(a1Range)
+		if a1Range.Start != a1Range.End {
+			if message := exceedsRange(values, a1Range, row, column, endRow, endColumn); message != "" {
+				writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", message)
+				return
+			}
+		}
+		updates = append(updates, update{sheet: sheet, row: row, column: column, values: values})
+	}
+
+	response := batchUpdateValuesResponseJson{SpreadSheetId: spreadSheet.id, Responses: make([]updateResponseJson, 0, len(updates))}
+	for _, update := range updates {
+		update.sheet.setValues(update.row, update.column, update.values)
+		response.TotalUpdatedRows += len(update.values)
+		response.TotalUpdatedCells += countCells(update.values)
+		response.Responses = append(response.Responses, updateResponseJson{
+			SpreadSheetId: spreadSheet.id,
+			UpdatedRange:  update.sheet.rangeOf(update.row, update.column, update.values),
+			UpdatedRows:   len(update.values),
+			UpdatedCells:  countCells(update.values),
+		})
+	}
+	writeJson(w, response)
+}
+
+// readValues decodes the values of a write request into their entered form.
+// If the request is invalid, an error is written and false is returned.
+func readValues(w http.ResponseWriter, r *http.Request) ([][]string, bool) {
+	inputOption := r.URL.Query().Get("valueInputOption")
+	if inputOption != inputRaw && inputOption != inputUserEntered {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "'valueInputOption' is required but not specified")
+		return nil, false
+	}
+	body := valueRangeJson{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return nil, false
+	}
+	return enterValues(body.Values, inputOption == inputRaw), true
+}
+
+// enterValues converts the values of a write request into their entered form
+func enterValues(values [][]any, raw bool) [][]string {
+	result := make([][]string, 0, len(values))
+	for _, row := range values {
+		entered := make([]string, 0, len(row))
+		for _, value := range row {
+			entered = append(entered, enterValue(value, raw))
+		}
+		result = append(result, entered)
+	}
+	return result
+}
+
+// exceedsRange returns an error message like the API if values do not fit into the range
+func exceedsRange(values [][]string, a1Range a1.Range, row int, column int, endRow int, endColumn int) string {
+	if a1Range.End.Row != 0 && row+len(values) > endRow {
+		return fmt.Sprintf("Requested writing within range [%s], but tried writing to row [%d]", a1Range.String(), row+len(values))
+	}
+	for _, rowValues := range values {
+		if a1Range.End.Column != 0 && column+len(rowValues) > endColumn {
+			return fmt.Sprintf("Requested writing within range [%s], but tried writing to column [%s]", a1Range.String(), columnName(column+len(rowValues)-1))
+		}
+	}
+	return ""
+}
+
+func (server *Server) handleBatchUpdate(w http.ResponseWriter, r *http.Request, spreadSheet *fakeSpreadSheet) {
+	body := batchUpdateRequestJson{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", err.Error())
+		return
+	}
+
+	// batch updates are atomic, either all requests are applied or none
+	snapshot := spreadSheet.clone()
+	snapshotNextSheetId := server.nextSheetId
+	replies := make([]map[string]any, 0, len(body.Requests))
+	for i, request := range body.Requests {
+		reply, err := server.applyRequest(spreadSheet, request)
+		if err != nil {
+			*spreadSheet = *snapshot
+			server.nextSheetId = snapshotNextSheetId
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT", fmt.Sprintf("Invalid requests[%d]: %s", i, err.Error()))
+			return
+		}
+		replies = append(replies, reply)
+	}
+
+	response := batchUpdateResponseJson{
+		SpreadSheetId: spreadSheet.id,
+		Replies:       replies,
+	}
+	if body.IncludeSpreadsheetInResponse {
+		updated := spreadSheet.toJson()
+		response.UpdatedSpreadsheet = &updated
+	}
+	writeJson(w, response)
+}
+
+func (server *Server) applyRequest(spreadSheet *fakeSpreadSheet, request batchRequestJson) (reply map[string]any, err error) {
+	switch {
+	case request.AddSheet != nil:
+		title := request.AddSheet.Properties.Title
+		if title == "" {
+			title = fmt.Sprintf("Sheet%d", len(spreadSheet.sheets)+1)
+		}
+		if spreadSheet.sheetByTitle(title) != nil {
+			return nil, fmt.Errorf("A sheet with the name \"%s\" already exists. Please enter another name.", title)
+		}
+		id := request.AddSheet.Properties.SheetId
+		if id == 0 {
+			id = server.nextSheetId
+			server.nextSheetId++
+		} else if spreadSheet.sheetById(id) != nil {
+			return nil, fmt.Errorf("Sheet with id %d already exists.", id)
+		}
+		sheet := &fakeSheet{
+			id:          id,
+			title:       title,
+			rowCount:    defaultRowCount,
+			columnCount: defaultColumnCount,
+		}
+		spreadSheet.sheets = append(spreadSheet.sheets, sheet)
+		return map[string]any{"addSheet": map[string]any{"properties": spreadSheet.propertiesJson(sheet)}}, nil
+	case request.DeleteSheet != nil:
+		index := spreadSheet.indexOf(request.DeleteSheet.SheetId)
+		if index < 0 {
+			return nil, fmt.Errorf("No grid with id: %d", request.DeleteSheet.SheetId)
+		}
+		if len(spreadSheet.sheets) == 1 {
+			return nil, fmt.Errorf("You can't remove all the sheets in a document.")
+		}
+		spreadSheet.sheets = append(spreadSheet.sheets[:index], spreadSheet.sheets[index+1:]...)
+		spreadSheet.removeNamedRanges(request.DeleteSheet.SheetId)
+		return map[string]any{}, nil
+	case request.DeleteDimension != nil:
+		dimensionRange := request.DeleteDimension.Range
+		sheet := spreadSheet.sheetById(dimensionRange.SheetId)
+		if sheet == nil {
+			return nil, fmt.Errorf("No grid with id: %d", dimensionRange.SheetId)
+		}
+		if dimensionRange.Dimension != "ROWS" {
+			return nil, fmt.Errorf("dimension %s is not supported by gstest", dimensionRange.Dimension)
+		}
+		if dimensionRange.StartIndex < 0 || dimensionRange.EndIndex <= dimensionRange.StartIndex || dimensionRange.EndIndex > sheet.rowCount {
+			return nil, fmt.Errorf("Invalid dimension range [%d, %d)", dimensionRange.StartIndex, dimensionRange.EndIndex)
+		}
+		if dimensionRange.EndIndex-dimensionRange.StartIndex >= sheet.rowCount {
+			return nil, fmt.Errorf("You can't delete all the rows on the sheet.")
+		}
+		sheet.deleteRows(dimensionRange.StartIndex, dimensionRange.EndIndex, 0, -1)
+		sheet.rowCount -= dimensionRange.EndIndex - dimensionRange.StartIndex
+		return map[string]any{}, nil
+	case request.DeleteRange != nil:
+		gridRange := request.DeleteRange.Range
+		sheet := spreadSheet.sheetById(gridRange.SheetId)
+		if sheet == nil {
+			return nil, fmt.Errorf("No grid with id: %d", gridRange.SheetId)
+		}
+		if request.DeleteRange.ShiftDimension != "ROWS" {
+			return nil, fmt.Errorf("shift dimension %s is not supported by gstest", request.DeleteRange.ShiftDimension)
+		}
+		endRow, endColumn := sheet.rowCount, -1
+		if gridRange.EndRowIndex != nil {
+			endRow = *gridRange.EndRowIndex
+		}
+		if gridRange.EndColumnIndex != nil {
+			endColumn = *gridRange.EndColumnIndex
+		}
+		sheet.deleteRows(gridRange.StartRowIndex, endRow, gridRange.StartColumnIndex, endColumn)
+		return map[string]any{}, nil
+	case request.DuplicateSheet != nil:
+		duplicate := request.DuplicateSheet
+		source := spreadSheet.sheetById(duplicate.SourceSheetId)
+		if source == nil {
+			return nil, fmt.Errorf("No grid with id: %d", duplicate.SourceSheetId)
+		}
+		title := duplicate.NewSheetName
+		if title == "" {
+			title = "Copy of " + source.title
+		}
+		if spreadSheet.sheetByTitle(title) != nil {
+			return nil, fmt.Errorf("A sheet with the name \"%s\" already exists. Please enter another name.", title)
+		}
+		id := duplicate.NewSheetId
+		if id == 0 {
+			id = server.nextSheetId
+			server.nextSheetId++
+		} else if spreadSheet.sheetById(id) != nil {
+			return nil, fmt.Errorf("Sheet with id %d already exists.", id)
+		}
+		index := 0
+		if duplicate.InsertSheetIndex != nil {
+			index = *duplicate.InsertSheetIndex
+		}
+		if index < 0 || index > len(spreadSheet.sheets) {
+			return nil, fmt.Errorf("Invalid insertSheetIndex %d", index)
+		}
+		copied := source.copy(id, title)
+		spreadSheet.sheets = append(spreadSheet.sheets[:index], append([]*fakeSheet{copied}, spreadSheet.sheets[index:]...)...)
+		return map[string]any{"duplicateSheet": map[string]any{"properties": spreadSheet.propertiesJson(copied)}}, nil
+	case request.UpdateSheetProperties != nil:
+		return map[string]any{}, spreadSheet.updateSheetProperties(*request.UpdateSheetProperties)
+	default:
+		return nil, fmt.Errorf("request kind is not supported by gstest")
+	}
+}
+
+// updateSheetProperties applies the properties selected by the field mask
+func (spreadSheet *fakeSpreadSheet) updateSheetProperties(request updateSheetPropertiesJson) error {
+	properties := request.Properties
+	sheet := spreadSheet.sheetById(properties.SheetId)
+	if sheet == nil {
+		return fmt.Errorf("No grid with id: %d", properties.SheetId)
+	}
+	if request.Fields == "" {
+		return fmt.Errorf("At least one field must be updated, but none were specified.")
+	}
+	gridProperties := gridPropertiesJson{}
+	if properties.GridProperties != nil {
+		gridProperties = *properties.GridProperties
+	}
+
+	for _, field := range strings.Split(request.Fields, ",") {
+		switch strings.TrimSpace(field) {
+		case "title":
+			if properties.Title == "" {
+				return fmt.Errorf("The sheet name cannot be empty.")
+			}
+			if other := spreadSheet.sheetByTitle(properties.Title); other != nil && other != sheet {
+				return fmt.Errorf("A sheet with the name \"%s\" already exists. Please enter another name.", properties.Title)
+			}
+			sheet.title = properties.Title
+		case "index":
+			if properties.Index < 0 {
+				return fmt.Errorf("Invalid index %d", properties.Index)
+			}
+			spreadSheet.moveSheet(sheet, properties.Index)
+		case "hidden":
+			sheet.hidden = properties.Hidden
+		case "tabColor":
+			sheet.tabColor = properties.TabColor
+		case "tabColorStyle":
+			sheet.tabColor = nil
+			if properties.TabColorStyle != nil {
+				sheet.tabColor = properties.TabColorStyle.RgbColor
+			}
+		case "gridProperties.frozenRowCount":
+			if gridProperties.FrozenRowCount < 0 || gridProperties.FrozenRowCount > sheet.rowCount {
+				return fmt.Errorf("Invalid frozen row count %d", gridProperties.FrozenRowCount)
+			}
+			sheet.frozenRows = gridProperties.FrozenRowCount
+		case "gridProperties.frozenColumnCount":
+			if gridProperties.FrozenColumnCount < 0 || gridProperties.FrozenColumnCount > sheet.columnCount {
+				return fmt.Errorf("Invalid frozen column count %d", gridProperties.FrozenColumnCount)
+			}
+			sheet.frozenColumns = gridProperties.FrozenColumnCount
+		default:
+			return fmt.Errorf("field '%s' is not supported by gstest", field)
+		}
+	}
+
+	for _, other := range spreadSheet.sheets {
+		if !other.hidden {
+			return nil
+		}
+	}
+	return fmt.Errorf("You can't hide all the sheets in a document.")
+}
+
+// moveSheet moves a sheet to the index, which refers to the positions before the move
+func (spreadSheet *fakeSpreadSheet) moveSheet(sheet *fakeSheet, index int) {
+	current := spreadSheet.indexOf(sheet.id)
+	if index > current {
+		index--
+	}
+	if index >= len(spreadSheet.sheets) {
+		index = len(spreadSheet.sheets) - 1
+	}
+	spreadSheet.sheets = append(spreadSheet.sheets[:current], spreadSheet.sheets[current+1:]...)
+	spreadSheet.sheets = append(spreadSheet.sheets[:index], append([]*fakeSheet{sheet}, spreadSheet.sheets[index:]...)...)
+}
+
+func (spreadSheet *fakeSpreadSheet) sheetByTitle(title string) *fakeSheet {
+	for _, sheet := range spreadSheet.sheets {
+		if sheet.title == title {
+			return sheet
+		}
+	}
+	return nil
+}
+
+func (spreadSheet *fakeSpreadSheet) sheetById(id int32) *fakeSheet {
+	index := spreadSheet.indexOf(id)
+	if index < 0 {
+		return nil
+	}
+	return spreadSheet.sheets[index]
+}
+
+func (spreadSheet *fakeSpreadSheet) indexOf(id int32) int {
+	for i, sheet := range spreadSheet.sheets {
+		if sheet.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func newFakeSpreadSheet(id string, title string) *fakeSpreadSheet {
+	return &fakeSpreadSheet{
+		id:       id,
+		title:    title,
+		locale:   defaultLocale,
+		timeZone: defaultTimeZone,
+	}
+}
+
+func (spreadSheet *fakeSpreadSheet) clone() *fakeSpreadSheet {
+	result := &fakeSpreadSheet{
+		id:          spreadSheet.id,
+		title:       spreadSheet.title,
+		locale:      spreadSheet.locale,
+		timeZone:    spreadSheet.timeZone,
+		public:      spreadSheet.public,
+		sheets:      make([]*fakeSheet, 0, len(spreadSheet.sheets)),
+		namedRanges: append([]fakeNamedRange{}, spreadSheet.namedRanges...),
+	}
+	for _, original := range spreadSheet.sheets {
+		copied := *original
+		copied.values = copyValues(original.values)
+		result.sheets = append(result.sheets, &copied)
+	}
+	return result
+}
+
+func (spreadSheet *fakeSpreadSheet) toJson() spreadSheetJson {
+	result := spreadSheetJson{
+		SpreadSheetId:  spreadSheet.id,
+		SpreadSheetUrl: fmt.Sprintf("https://docs.google.com/spreadsheets/d/%s/edit", spreadSheet.id),
+		Properties: spreadSheetPropertiesJson{
+			Title:    spreadSheet.title,
+			Locale:   spreadSheet.locale,
+			TimeZone: spreadSheet.timeZone,
+		},
+		Sheets: make([]sheetJson, 0, len(spreadSheet.sheets)),
+	}
+	for _, sheet := range spreadSheet.sheets {
+		result.Sheets = append(result.Sheets, sheetJson{Properties: spreadSheet.propertiesJson(sheet)})
+	}
+	for _, namedRange := range spreadSheet.namedRanges {
+		result.NamedRanges = append(result.NamedRanges, namedRange.toJson())
+	}
+	return result
+}
+
+// removeNamedRanges deletes all named ranges of a sheet
+func (spreadSheet *fakeSpreadSheet) removeNamedRanges(sheetId int32) {
+	remaining := make([]fakeNamedRange, 0, len(spreadSheet.namedRanges))
+	for _, namedRange := range spreadSheet.namedRanges {
+		if namedRange.sheetId != sheetId {
+			remaining = append(remaining, namedRange)
+		}
+	}
+	spreadSheet.namedRanges = remaining
+}
+
+func (namedRange fakeNamedRange) toJson() namedRangeJson {
+	result := namedRangeJson{
+		NamedRangeId: namedRange.id,
+		Name:         namedRange.name,
+		Range: gridRangeJson{
+			SheetId:          namedRange.sheetId,
+			StartRowIndex:    namedRange.a1Range.StartRow() - 1,
+			StartColumnIndex: namedRange.a1Range.StartColumn() - 1,
+		},
+	}
+	if endRow := namedRange.a1Range.End.Row; endRow != 0 {
+		result.Range.EndRowIndex = &endRow
+	}
+	if endColumn := namedRange.a1Range.End.Column; endColumn != 0 {
+		result.Range.EndColumnIndex = &endColumn
+	}
+	return result
+}
+
+func (spreadSheet *fakeSpreadSheet) propertiesJson(sheet *fakeSheet) sheetPropertiesJson {
+	result := sheetPropertiesJson{
+		SheetId:   sheet.id,
+		Title:     sheet.title,
+		Index:     spreadSheet.indexOf(sheet.id),
+		SheetType: "GRID",
+		Hidden:    sheet.hidden,
+		GridProperties: &gridPropertiesJson{
+			RowCount:          sheet.rowCount,
+			ColumnCount:       sheet.columnCount,
+			FrozenRowCount:    sheet.frozenRows,
+			FrozenColumnCount: sheet.frozenColumns,
+		},
+	}
+	if sheet.tabColor != nil {
+		result.TabColor = sheet.tabColor
+		result.TabColorStyle = &colorStyleJson{RgbColor: sheet.tabColor}
+	}
+	return result
+}
+
+// copy returns a copy of the sheet with the given id and title
+func (sheet *fakeSheet) copy(id int32, title string) *fakeSheet {
+	result := *sheet
+	result.id = id
+	result.title = title
+	result.values = copyValues(sheet.values)
+	return &result
+}
+
+// setValues writes values into the sheet starting at the zero based row and column,
+// growing the grid if needed
+func (sheet *fakeSheet) setValues(row int, column int, values [][]string) {
+	for i, rowValues := range values {
+		for len(sheet.values) <= row+i {
+			sheet.values = append(sheet.values, []string{})
+		}
+		target := sheet.values[row+i]
+		for len(target) < column+len(rowValues) {
+			target = append(target, "")
+		}
+		copy(target[column:], rowValues)
+		sheet.values[row+i] = target
+
+		if len(target) > sheet.columnCount {
+			sheet.columnCount = len(target)
+		}
+	}
+	if len(sheet.values) > sheet.rowCount {
+		sheet.rowCount = len(sheet.values)
+	}
+}
+
+// bounds converts a range into zero based, half open row and column indexes.
+// Unbounded ends are limited by the size of the grid.
+func (sheet *fakeSheet) bounds(a1Range a1.Range) (row int, column int, endRow int, endColumn int) {
+	row, column = a1Range.StartRow()-1, a1Range.StartColumn()-1
+	endRow, endColumn = a1Range.End.Row, a1Range.End.Column
+	if endRow == 0 {
+		endRow = sheet.rowCount
+	}
+	if endColumn == 0 {
+		endColumn = sheet.columnCount
+	}
+	return row, column, endRow, endColumn
+}
+
+// valuesBetween returns a copy of the values within the given zero based, half open bounds
+func (sheet *fakeSheet) valuesBetween(row int, column int, endRow int, endColumn int) [][]string {
+	result := make([][]string, 0)
+	for i := row; i < endRow && i < len(sheet.values); i++ {
+		rowValues := make([]string, 0)
+		for j := column; j < endColumn && j < len(sheet.values[i]); j++ {
+			rowValues = append(rowValues, sheet.values[i][j])
+		}
+		result = append(result, rowValues)
+	}
+	return result
+}
+
+// deleteRows removes the zero based, half open rows within the given columns
+// and shifts the cells below up. An end column of -1 covers all columns.
+func (sheet *fakeSheet) deleteRows(row int, endRow int, column int, endColumn int) {
+	if row >= len(sheet.values) {
+		return
+	}
+	if endRow > len(sheet.values) {
+		endRow = len(sheet.values)
+	}
+	if column == 0 && endColumn < 0 {
+		sheet.values = append(sheet.values[:row], sheet.values[endRow:]...)
+		return
+	}
+
+	count := endRow - row
+	for i := row; i < len(sheet.values); i++ {
+		var source []string
+		if i+count < len(sheet.values) {
+			source = sheet.values[i+count]
+		}
+		target := sheet.values[i]
+		for j := column; endColumn < 0 || j < endColumn; j++ {
+			if j >= len(target) && j >= len(source) {
+				break
+			}
+			for len(target) <= j {
+				target = append(target, "")
+			}
+			target[j] = ""
+			if j < len(source) {
+				target[j] = source[j]
+			}
+		}
+		sheet.values[i] = target
+	}
+}
+
+func (sheet *fakeSheet) clearBetween(row int, column int, endRow int, endColumn int) {
+	for i := row; i < endRow && i < len(sheet.values); i++ {
+		for j := column; j < endColumn && j < len(sheet.values[i]); j++ {
+			sheet.values[i][j] = ""
+		}
+	}
+}
+
+func (sheet *fakeSheet) rangeBetween(row int, column int, endRow int, endColumn int) string {
+	return fmt.Sprintf("%s!%s%d:%s%d", quoteSheetName(sheet.title), columnName(column), row+1, columnName(endColumn-1), endRow)
+}
+
+func (sheet *fakeSheet) gridRange() string {
+	return fmt.Sprintf("%s!A1:%s%d", quoteSheetName(sheet.title), columnName(sheet.columnCount-1), sheet.rowCount)
+}
+
+func (sheet *fakeSheet) rangeOf(row int, column int, values [][]string) string {
+	width := 1
+	for _, rowValues := range values {
+		if len(rowValues) > width {
+			width = len(rowValues)
+		}
+	}
+	height := len(values)
+	if height == 0 {
+		height = 1
+	}
+	return fmt.Sprintf("%s!%s%d:%s%d", quoteSheetName(sheet.title),
+		columnName(column), row+1, columnName(column+width-1), row+height)
+}
+
+// trimValues removes trailing empty cells and rows like the API does
+func trimValues(values [][]string) [][]string {
+	result := make([][]string, 0, len(values))
+	for _, row := range values {
+		end := len(row)
+		for end > 0 && row[end-1] == "" {
+			end--
+		}
+		result = append(result, row[:end])
+	}
+	end := len(result)
+	for end > 0 && len(result[end-1]) == 0 {
+		end--
+	}
+	return result[:end]
+}
+
+func copyValues(values [][]string) [][]string {
+	if values == nil {
+		return nil
+	}
+	result := make([][]string, 0, len(values))
+	for _, row := range values {
+		result = append(result, append([]string{}, row...))
+	}
+	return result
+}
+
+func countCells(values [][]string) int {
+	result := 0
+	for _, row := range values {
+		result += len(row)
+	}
+	return result
+}
+
+// columnName converts a zero based column index into its letter representation
+func columnName(column int) string {
+	name := ""
+	for column >= 0 {
+		name = string(rune('A'+column%26)) + name
+		column = column/26 - 1
+	}
+	return name
+}
+
+func quoteSheetName(name string) string {
+	return a1.QuoteSheetName(name)
+}
+
+func writeJson(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code int, status string, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(errorResponseJson{
+		Error: errorJson{
+			Code:    code,
+			Message: message,
+			Status:  status,
+		},
+	})
+}
+
+func statusName(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return "INVALID_ARGUMENT"
+	case http.StatusUnauthorized:
+		return "UNAUTHENTICATED"
+	case http.StatusForbidden:
+		return "PERMISSION_DENIED"
+	case http.StatusNotFound:
+		return "NOT_FOUND"
+	case http.StatusTooManyRequests:
+		return "RESOURCE_EXHAUSTED"
+	case http.StatusServiceUnavailable:
+		return "UNAVAILABLE"
+	default:
+		return "INTERNAL"
+	}
+}
+
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (transport *rewriteTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	rewritten := request.Clone(request.Context())
+	rewritten.URL.Scheme = transport.target.Scheme
+	rewritten.URL.Host = transport.target.Host
+	rewritten.Host = transport.target.Host
+	return transport.base.RoundTrip(rewritten)
+}
@@ -0,0 +1,448 @@
+package gstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/color"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
+)
+
+func Test_Server_Create_Append_Read(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+
+	id, err := wrapper.CreateSheet(context.Background(), "spreadSheetId", "sheetName")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	foundId, err := wrapper.GetSheetId(context.Background(), "spreadSheetId", "sheetName")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if id != foundId {
+		t.Errorf("expected id %d but found %d", id, foundId)
+	}
+
+	err = wrapper.AppendToSheet(context.Background(), "spreadSheetId", "sheetName", [][]string{{"0", "1"}})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	err = wrapper.AppendToSheet(context.Background(), "spreadSheetId", "sheetName", [][]string{{"2", "3"}})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	records, err := wrapper.GetRangeRecords(context.Background(), "spreadSheetId", a1.Range{Sheet: "sheetName"})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if expected := [][]string{{"0", "1"}, {"2", "3"}}; !reflect.DeepEqual(expected, records) {
+		t.Errorf("expected %v but found %v", expected, records)
+	}
+}
+
+func Test_Server_Clear(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a", "b"}})
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+
+	err := wrapper.ClearSheet(context.Background(), "spreadSheetId", "sheetName")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	if actual := server.Values("spreadSheetId", "sheetName"); len(actual) != 0 {
+		t.Errorf("expected no values but found %+v", actual)
+	}
+}
+
+func Test_Server_Delete(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	id := server.AddSheet("spreadSheetId", "sheetName", nil)
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+
+	err := wrapper.DeleteSheet(context.Background(), "spreadSheetId", id)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	expected := []string{"Sheet1"}
+	if actual := server.SheetNames("spreadSheetId"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v but found %+v", expected, actual)
+	}
+}
+
+func Test_Server_Delete_Last_Sheet(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+
+	err := wrapper.DeleteSheet(context.Background(), "spreadSheetId", 0)
+	if err == nil {
+		t.Error("expected error when removing the last sheet")
+	}
+}
+
+func Test_Server_Create_Duplicate(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+
+	_, err := wrapper.CreateSheet(context.Background(), "spreadSheetId", "sheetName")
+	if err == nil {
+		t.Error("expected error when creating a sheet twice")
+	}
+}
+
+func Test_Server_Unknown_SpreadSheet(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	response, err := server.Client().Get("https://sheets.googleapis.com/v4/spreadsheets/unknown")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status %d but found %d", http.StatusNotFound, response.StatusCode)
+	}
+}
+
+func Test_Server_AddSheet_Values(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	expected := [][]string{{"a", "b"}, {}, {"c"}}
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a", "b", ""}, {}, {"c"}, {}})
+
+	if actual := server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v but found %+v", expected, actual)
+	}
+}
+
+func Test_columnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"}
+	for column, expected := range tests {
+		if actual := columnName(column); actual != expected {
+			t.Errorf("expected %s for %d but found %s", expected, column, actual)
+		}
+	}
+}
+
+func Test_Server_FailRequests(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	server.FailRequests(http.StatusTooManyRequests, 1)
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+
+	_, err := wrapper.GetSheetId(context.Background(), "spreadSheetId", "Sheet1")
+	if err == nil {
+		t.Error("expected first request to fail")
+	}
+	_, err = wrapper.GetSheetId(context.Background(), "spreadSheetId", "Sheet1")
+	if err != nil {
+		t.Errorf("found error %+v", err)
+	}
+}
+
+func Test_Server_Range(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a", "b", "c"}, {"d", "e", "f"}, {"g", "h", "i"}})
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+	a1Range, err := a1.ParseRange("sheetName!B2:C")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	records, err := wrapper.GetRangeRecords(context.Background(), "spreadSheetId", a1Range)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if expected := [][]string{{"e", "f"}, {"h", "i"}}; !reflect.DeepEqual(expected, records) {
+		t.Errorf("expected %v but found %v", expected, records)
+	}
+
+	err = wrapper.ClearRange(context.Background(), "spreadSheetId", a1Range)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	expected := [][]string{{"a", "b", "c"}, {"d"}, {"g"}}
+	if actual := server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v but found %+v", expected, actual)
+	}
+
+	err = wrapper.AppendToRange(context.Background(), "spreadSheetId", a1Range, [][]string{{"1", "2"}})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	expected = [][]string{{"a", "b", "c"}, {"d", "1", "2"}, {"g"}}
+	if actual := server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v but found %+v", expected, actual)
+	}
+}
+
+func Test_Server_Update_Exceeding_Range(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+	a1Range, err := a1.ParseRange("sheetName!A1:B1")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	err = wrapper.UpdateValues(context.Background(), "spreadSheetId", a1Range, [][]string{{"a", "b"}, {"c", "d"}})
+	if err == nil {
+		t.Error("expected error when writing beyond the range")
+	}
+	err = wrapper.UpdateValues(context.Background(), "spreadSheetId", a1Range, [][]string{{"a", "b"}})
+	if err != nil {
+		t.Errorf("found error %+v", err)
+	}
+}
+
+func Test_Server_BatchUpdateValues(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a", "b", "c"}})
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+	ranges := []a1.Range{
+		{Sheet: "sheetName", Start: a1.Cell{Column: 1, Row: 1}, End: a1.Cell{Column: 1, Row: 1}},
+		{Sheet: "sheetName", Start: a1.Cell{Column: 2, Row: 1}, End: a1.Cell{Column: 3, Row: 1}},
+	}
+
+	// none of the ranges is written if one exceeds its range
+	err := wrapper.BatchUpdateValues(context.Background(), "spreadSheetId", ranges, [][][]string{{{"x"}}, {{"y", "z", "w"}}})
+	if err == nil {
+		t.Error("expected error when writing beyond the range")
+	}
+	if expected, actual := [][]string{{"a", "b", "c"}}, server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+
+	err = wrapper.BatchUpdateValues(context.Background(), "spreadSheetId", ranges, [][][]string{{{"x"}}, {{"y", "z"}}})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if expected, actual := [][]string{{"x", "y", "z"}}, server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+}
+
+func Test_Server_NamedRange(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	id := server.AddSheet("spreadSheetId", "sheetName", nil)
+	err := server.AddNamedRange("spreadSheetId", "Block", "sheetName!B2:C5")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+
+	actual, err := wrapper.GetNamedRange(context.Background(), "spreadSheetId", "Block")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if actual.String() != "sheetName!B2:C5" {
+		t.Errorf("expected 'sheetName!B2:C5' but found '%s'", actual.String())
+	}
+
+	err = wrapper.DeleteSheet(context.Background(), "spreadSheetId", id)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	_, err = wrapper.GetNamedRange(context.Background(), "spreadSheetId", "Block")
+	if !errors.Is(err, apiwrapper.ErrNotExist) {
+		t.Errorf("expected ErrNotExist but found %+v", err)
+	}
+}
+
+func Test_Server_DeleteRows(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	id := server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a", "b", "c"}, {"d", "e", "f"}, {"g", "h", "i"}})
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+
+	a1Range, _ := a1.ParseRange("sheetName!B1:B1")
+	err := wrapper.DeleteRows(context.Background(), "spreadSheetId", id, a1Range)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	expected := [][]string{{"a", "e", "c"}, {"d", "h", "f"}, {"g", "", "i"}}
+	if actual := server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v but found %+v", expected, actual)
+	}
+
+	a1Range, _ = a1.ParseRange("sheetName!1:2")
+	err = wrapper.DeleteRows(context.Background(), "spreadSheetId", id, a1Range)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	expected = [][]string{{"g", "", "i"}}
+	if actual := server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v but found %+v", expected, actual)
+	}
+
+	a1Range, _ = a1.ParseRange("sheetName!1:1")
+	err = wrapper.DeleteRows(context.Background(), "spreadSheetId", 42, a1Range)
+	if err == nil {
+		t.Error("expected error for unknown sheet")
+	}
+}
+
+func Test_Server_UpdateSheetProperties(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	aId := server.AddSheet("spreadSheetId", "A", nil)
+	server.AddSheet("spreadSheetId", "B", nil)
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+	ctx := context.Background()
+
+	// indexes refer to the positions before the move
+	if err := wrapper.MoveSheet(ctx, "spreadSheetId", 0, 2); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if expected, actual := []string{"A", "Sheet1", "B"}, server.SheetNames("spreadSheetId"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+	if err := wrapper.MoveSheet(ctx, "spreadSheetId", aId, 10); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if expected, actual := []string{"Sheet1", "B", "A"}, server.SheetNames("spreadSheetId"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+
+	if err := wrapper.RenameSheet(ctx, "spreadSheetId", aId, "B"); err == nil {
+		t.Error("expected error for duplicate name")
+	}
+	if err := wrapper.RenameSheet(ctx, "spreadSheetId", aId, "C"); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if err := wrapper.SetTabColor(ctx, "spreadSheetId", aId, color.NRGBA{R: 255, A: 255}); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if err := wrapper.SetFrozen(ctx, "spreadSheetId", aId, 1, 2); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if err := wrapper.SetSheetHidden(ctx, "spreadSheetId", aId, true); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	properties, err := wrapper.GetSheetProperties(ctx, "spreadSheetId", "C")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	expected := apiwrapper.SheetProperties{
+		Id: aId, Title: "C", Index: 2, RowCount: defaultRowCount, ColumnCount: defaultColumnCount,
+		FrozenRowCount: 1, FrozenColumnCount: 2, Hidden: true, TabColor: color.NRGBA{R: 255, A: 255},
+	}
+	if !reflect.DeepEqual(expected, properties) {
+		t.Errorf("expected %+v but found %+v", expected, properties)
+	}
+
+	if err := wrapper.SetTabColor(ctx, "spreadSheetId", aId, nil); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	properties, err = wrapper.GetSheetProperties(ctx, "spreadSheetId", "C")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if properties.TabColor != nil {
+		t.Errorf("expected no tab color but found %v", properties.TabColor)
+	}
+}
+
+func Test_Server_Hide_All_Sheets(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	wrapper := apiwrapper.NewSheetsApiWrapper(server.Client())
+
+	err := wrapper.SetSheetHidden(context.Background(), "spreadSheetId", 0, true)
+	apiError := &apiwrapper.APIError{}
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected bad request but found %v", err)
+	}
+}
+
+func Test_Server_Export(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheet name", [][]string{{"a", "b,c"}, {}, {"1"}})
+	url := server.URL + "/spreadsheets/d/spreadSheetId/gviz/tq?tqx=out:csv&sheet=sheet+name"
+
+	response, err := server.Client().Get(url)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response.Body.Close()
+	if response.Header.Get("Content-Type") == "text/csv; charset=utf-8" {
+		t.Errorf("expected sign-in page for a private spreadsheet")
+	}
+
+	server.SetPublic("spreadSheetId", true)
+	response, err = server.Client().Get(url)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	buffer := new(bytes.Buffer)
+	_, err = buffer.ReadFrom(response.Body)
+	response.Body.Close()
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	expected := "a,\"b,c\"\n,\n1,\n"
+	if buffer.String() != expected {
+		t.Errorf("expected '%s' but found '%s'", expected, buffer.String())
+	}
+}
+
+func Test_Server_APIKey(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a"}})
+	url := server.URL + "/v4/spreadsheets/spreadSheetId/values/sheetName?key=key"
+
+	response, err := server.Client().Get(url)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusForbidden {
+		t.Errorf("expected status %d but found %d", http.StatusForbidden, response.StatusCode)
+	}
+
+	server.SetPublic("spreadSheetId", true)
+	response, err = server.Client().Get(url)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d but found %d", http.StatusOK, response.StatusCode)
+	}
+
+	response, err = server.Client().Post(server.URL+"/v4/spreadsheets/spreadSheetId/values/sheetName:clear?key=key", "application/json", nil)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status %d but found %d", http.StatusUnauthorized, response.StatusCode)
+	}
+}
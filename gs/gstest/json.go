@@ -0,0 +1,173 @@
+package gstest
+
+// payloads of the Google Sheets v4 API as described in
+// https://developers.google.com/sheets/api/reference/rest
+
+type spreadSheetJson struct {
+	SpreadSheetId  string                    `json:"spreadsheetId"`
+	SpreadSheetUrl string                    `json:"spreadsheetUrl,omitempty"`
+	Properties     spreadSheetPropertiesJson `json:"properties"`
+	Sheets         []sheetJson               `json:"sheets"`
+	NamedRanges    []namedRangeJson          `json:"namedRanges,omitempty"`
+}
+
+type namedRangeJson struct {
+	NamedRangeId string        `json:"namedRangeId"`
+	Name         string        `json:"name"`
+	Range        gridRangeJson `json:"range"`
+}
+
+type gridRangeJson struct {
+	SheetId          int32 `json:"sheetId"`
+	StartRowIndex    int   `json:"startRowIndex,omitempty"`
+	EndRowIndex      *int  `json:"endRowIndex,omitempty"`
+	StartColumnIndex int   `json:"startColumnIndex,omitempty"`
+	EndColumnIndex   *int  `json:"endColumnIndex,omitempty"`
+}
+
+type spreadSheetPropertiesJson struct {
+	Title    string `json:"title"`
+	Locale   string `json:"locale,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+type sheetJson struct {
+	Properties sheetPropertiesJson `json:"properties"`
+}
+
+type sheetPropertiesJson struct {
+	SheetId        int32               `json:"sheetId"`
+	Title          string              `json:"title,omitempty"`
+	Index          int                 `json:"index"`
+	SheetType      string              `json:"sheetType,omitempty"`
+	Hidden         bool                `json:"hidden,omitempty"`
+	GridProperties *gridPropertiesJson `json:"gridProperties,omitempty"`
+	TabColor       *rgbColorJson       `json:"tabColor,omitempty"`
+	TabColorStyle  *colorStyleJson     `json:"tabColorStyle,omitempty"`
+}
+
+type gridPropertiesJson struct {
+	RowCount          int `json:"rowCount,omitempty"`
+	ColumnCount       int `json:"columnCount,omitempty"`
+	FrozenRowCount    int `json:"frozenRowCount,omitempty"`
+	FrozenColumnCount int `json:"frozenColumnCount,omitempty"`
+}
+
+type colorStyleJson struct {
+	RgbColor   *rgbColorJson `json:"rgbColor,omitempty"`
+	ThemeColor string        `json:"themeColor,omitempty"`
+}
+
+type rgbColorJson struct {
+	Red   float64  `json:"red,omitempty"`
+	Green float64  `json:"green,omitempty"`
+	Blue  float64  `json:"blue,omitempty"`
+	Alpha *float64 `json:"alpha,omitempty"`
+}
+
+type valueRangeJson struct {
+	Range          string  `json:"range"`
+	MajorDimension string  `json:"majorDimension"`
+	Values         [][]any `json:"values,omitempty"`
+}
+
+type appendResponseJson struct {
+	SpreadSheetId string             `json:"spreadsheetId"`
+	TableRange    string             `json:"tableRange,omitempty"`
+	Updates       updateResponseJson `json:"updates"`
+}
+
+type batchUpdateValuesRequestJson struct {
+	ValueInputOption string           `json:"valueInputOption"`
+	Data             []valueRangeJson `json:"data"`
+}
+
+type batchUpdateValuesResponseJson struct {
+	SpreadSheetId     string               `json:"spreadsheetId"`
+	TotalUpdatedRows  int                  `json:"totalUpdatedRows"`
+	TotalUpdatedCells int                  `json:"totalUpdatedCells"`
+	Responses         []updateResponseJson `json:"responses"`
+}
+
+type updateResponseJson struct {
+	SpreadSheetId string `json:"spreadsheetId"`
+	UpdatedRange  string `json:"updatedRange"`
+	UpdatedRows   int    `json:"updatedRows"`
+	UpdatedCells  int    `json:"updatedCells"`
+}
+
+type clearResponseJson struct {
+	SpreadSheetId string `json:"spreadsheetId"`
+	ClearedRange  string `json:"clearedRange"`
+}
+
+type batchUpdateRequestJson struct {
+	Requests                     []batchRequestJson `json:"requests"`
+	IncludeSpreadsheetInResponse bool               `json:"includeSpreadsheetInResponse"`
+}
+
+type batchRequestJson struct {
+	AddSheet        *addSheetJson        `json:"addSheet,omitempty"`
+	DeleteSheet     *deleteSheetJson     `json:"deleteSheet,omitempty"`
+	DeleteDimension *deleteDimensionJson `json:"deleteDimension,omitempty"`
+	DeleteRange     *deleteRangeJson     `json:"deleteRange,omitempty"`
+
+	UpdateSheetProperties *updateSheetPropertiesJson `json:"updateSheetProperties,omitempty"`
+	DuplicateSheet        *duplicateSheetJson        `json:"duplicateSheet,omitempty"`
+}
+
+type duplicateSheetJson struct {
+	SourceSheetId    int32  `json:"sourceSheetId"`
+	InsertSheetIndex *int   `json:"insertSheetIndex,omitempty"`
+	NewSheetId       int32  `json:"newSheetId,omitempty"`
+	NewSheetName     string `json:"newSheetName,omitempty"`
+}
+
+type copySheetToRequestJson struct {
+	DestinationSpreadSheetId string `json:"destinationSpreadsheetId"`
+}
+
+type updateSheetPropertiesJson struct {
+	Properties sheetPropertiesJson `json:"properties"`
+	Fields     string              `json:"fields"`
+}
+
+type deleteDimensionJson struct {
+	Range dimensionRangeJson `json:"range"`
+}
+
+type dimensionRangeJson struct {
+	SheetId    int32  `json:"sheetId"`
+	Dimension  string `json:"dimension"`
+	StartIndex int    `json:"startIndex"`
+	EndIndex   int    `json:"endIndex"`
+}
+
+type deleteRangeJson struct {
+	Range          gridRangeJson `json:"range"`
+	ShiftDimension string        `json:"shiftDimension"`
+}
+
+type addSheetJson struct {
+	Properties sheetPropertiesJson `json:"properties"`
+}
+
+type deleteSheetJson struct {
+	SheetId int32 `json:"sheetId"`
+}
+
+type batchUpdateResponseJson struct {
+	SpreadSheetId      string           `json:"spreadsheetId"`
+	Replies            []map[string]any `json:"replies"`
+	UpdatedSpreadsheet *spreadSheetJson `json:"updatedSpreadsheet,omitempty"`
+}
+
+type errorResponseJson struct {
+	Error errorJson `json:"error"`
+}
+
+type errorJson struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
@@ -0,0 +1,112 @@
+package gstest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// options of the values endpoints
+const (
+	inputRaw         = "RAW"
+	inputUserEntered = "USER_ENTERED"
+
+	renderFormatted   = "FORMATTED_VALUE"
+	renderUnformatted = "UNFORMATTED_VALUE"
+	renderFormula     = "FORMULA"
+)
+
+// Cells are stored in the form they would be typed into the UI.
+// Text which would be parsed as number, boolean or formula is prefixed with "'",
+// the same way Google Sheets keeps text entered with a leading apostrophe.
+//
+// The fake does neither evaluate formulas nor recognize dates.
+
+// enterValue converts a value of a write request into its entered form
+func enterValue(value any, raw bool) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if raw && (strings.HasPrefix(typed, "'") || isParsed(typed)) {
+			return "'" + typed
+		}
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(typed))
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+// isParsed returns true if Google Sheets would not store the entered value as text
+func isParsed(value string) bool {
+	if strings.HasPrefix(value, "=") {
+		return true
+	}
+	if _, ok := parseBool(value); ok {
+		return true
+	}
+	_, ok := parseNumber(value)
+	return ok
+}
+
+// displayValue returns an entered value as displayed in the UI
+func displayValue(value string) string {
+	return strings.TrimPrefix(value, "'")
+}
+
+// renderValue returns an entered value according to the valueRenderOption of a read
+func renderValue(value string, renderOption string) any {
+	if strings.HasPrefix(value, "'") {
+		return value[1:]
+	}
+	if renderOption == renderFormatted || strings.HasPrefix(value, "=") {
+		return value
+	}
+	if boolean, ok := parseBool(value); ok {
+		return boolean
+	}
+	if number, ok := parseNumber(value); ok {
+		return number
+	}
+	return value
+}
+
+func renderValues(values [][]string, renderOption string) [][]any {
+	result := make([][]any, 0, len(values))
+	for _, row := range values {
+		rendered := make([]any, 0, len(row))
+		for _, value := range row {
+			rendered = append(rendered, renderValue(value, renderOption))
+		}
+		result = append(result, rendered)
+	}
+	return result
+}
+
+func parseBool(value string) (bool, bool) {
+	switch strings.ToUpper(value) {
+	case "TRUE":
+		return true, true
+	case "FALSE":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+func parseNumber(value string) (float64, bool) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" || strings.Trim(trimmed, "0123456789+-.eE") != "" {
+		// reject hex notation, "NaN" or "Inf" which are accepted by strconv
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
@@ -0,0 +1,45 @@
+package gstest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_enterValue(t *testing.T) {
+	tests := []struct {
+		value    any
+		raw      bool
+		expected string
+	}{
+		{"text", true, "text"},
+		{"1.5", true, "'1.5"},
+		{"=A1", true, "'=A1"},
+		{"TRUE", true, "'TRUE"},
+		{"'quoted", true, "''quoted"},
+		{"1.5", false, "1.5"},
+		{"=A1", false, "=A1"},
+		{1.5, true, "1.5"},
+		{false, true, "FALSE"},
+		{nil, true, ""},
+	}
+	for _, test := range tests {
+		if actual := enterValue(test.value, test.raw); actual != test.expected {
+			t.Errorf("expected '%s' for %v (raw %t) but found '%s'", test.expected, test.value, test.raw, actual)
+		}
+	}
+}
+
+func Test_renderValues(t *testing.T) {
+	values := [][]string{{"text", "1234.5", "true", "=SUM(A1:A2)", "'42", "NaN"}}
+
+	tests := map[string][][]any{
+		renderFormatted:   {{"text", "1234.5", "true", "=SUM(A1:A2)", "42", "NaN"}},
+		renderUnformatted: {{"text", 1234.5, true, "=SUM(A1:A2)", "42", "NaN"}},
+		renderFormula:     {{"text", 1234.5, true, "=SUM(A1:A2)", "42", "NaN"}},
+	}
+	for renderOption, expected := range tests {
+		if actual := renderValues(values, renderOption); !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected %v for %s but found %v", expected, renderOption, actual)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package gs
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
+	"golang.org/x/oauth2"
+)
+
+// Option configures how a sheet is accessed
+type Option func(*options)
+
+type options struct {
+	httpClient  *http.Client
+	tokenSource oauth2.TokenSource
+	endpoint    string
+	retryPolicy *RetryPolicy
+	rateLimiter *RateLimiter
+	// authentication with Application Default Credentials or the metadata server
+	defaultCredentials bool
+	metadataEndpoint   string
+	// domain-wide delegation and impersonation of service accounts
+	subject     string
+	impersonate string
+	delegates   []string
+	iamEndpoint string
+	apiKey      string
+
+	overwriteAnchor string
+	a1Range         string
+	inputOption     ValueInputOption
+	renderOption    ValueRenderOption
+	dateTimeOption  DateTimeRenderOption
+	pageSize        int
+	writeBufferSize int
+	force           bool
+	// position of a copied sheet, nil adds it as last sheet
+	index *int
+	// read through the csv export without credentials, see OpenPublicSheet
+	publicAccess bool
+}
+
+// RetryPolicy configures how requests failing with a quota (429) or a server (5xx)
+// error are retried. Appending rows is only retried after a 429 response,
+// since a retry after a server error could duplicate data.
+type RetryPolicy = apiwrapper.RetryPolicy
+
+// DefaultRetryPolicy returns a policy with exponential backoff as recommended
+// by Google. Up to 5 attempts are made with delays from 1 to 32 seconds.
+func DefaultRetryPolicy() RetryPolicy {
+	return apiwrapper.DefaultRetryPolicy()
+}
+
+// RateLimiter blocks requests which would exceed the Sheets API quotas.
+// Read and write requests are limited independently.
+// Share one limiter across all sheets which count against the same quota.
+type RateLimiter = apiwrapper.RateLimiter
+
+// Default quotas per user and project, see https://developers.google.com/sheets/api/limits
+const (
+	DefaultReadRequestsPerMinute  = apiwrapper.DefaultReadRequestsPerMinute
+	DefaultWriteRequestsPerMinute = apiwrapper.DefaultWriteRequestsPerMinute
+)
+
+// NewRateLimiter creates a limiter allowing the given number of read and write
+// requests per minute. A value lower than 1 disables the respective limit.
+func NewRateLimiter(readRequestsPerMinute int, writeRequestsPerMinute int) *RateLimiter {
+	return apiwrapper.NewRateLimiter(readRequestsPerMinute, writeRequestsPerMinute)
+}
+
+// ValueInputOption determines how written values are interpreted by Google Sheets
+type ValueInputOption = apiwrapper.ValueInputOption
+
+const (
+	// InputRaw stores values as they are, e.g. "=SUM(A1:A3)" is stored as text (default)
+	InputRaw = apiwrapper.InputRaw
+	// InputUserEntered parses values as if they were typed into the UI,
+	// so formulas are evaluated and numbers and dates are recognized
+	InputUserEntered = apiwrapper.InputUserEntered
+)
+
+// ValueRenderOption determines how values are returned when reading
+type ValueRenderOption = apiwrapper.ValueRenderOption
+
+const (
+	// RenderFormatted returns values as displayed in the UI, e.g. "1,234.50 €" (default)
+	RenderFormatted = apiwrapper.RenderFormatted
+	// RenderUnformatted returns numbers and booleans without formatting, e.g. 1234.5
+	RenderUnformatted = apiwrapper.RenderUnformatted
+	// RenderFormula returns the formulas of computed cells instead of their results
+	RenderFormula = apiwrapper.RenderFormula
+)
+
+// DateTimeRenderOption determines how dates and times are returned when reading.
+// It is ignored if values are read with RenderFormatted.
+type DateTimeRenderOption = apiwrapper.DateTimeRenderOption
+
+const (
+	// DateTimeSerialNumber returns dates as number of days since December 30th 1899 (default)
+	DateTimeSerialNumber = apiwrapper.DateTimeSerialNumber
+	// DateTimeFormattedString returns dates as formatted by the number format of the cell
+	DateTimeFormattedString = apiwrapper.DateTimeFormattedString
+)
+
+// WithHTTPClient uses the given client for all requests instead of creating
+// one from service account credentials. The client is expected to handle
+// authentication, e.g. a client created with oauth2.NewClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTokenSource authenticates all requests with tokens of the given source
+// instead of service account credentials.
+func WithTokenSource(tokenSource oauth2.TokenSource) Option {
+	return func(o *options) {
+		o.tokenSource = tokenSource
+	}
+}
+
+// WithDefaultCredentials authenticates all requests with Application Default Credentials
+// instead of service account credentials. They are taken from the key file named by the
+// GOOGLE_APPLICATION_CREDENTIALS environment variable, the user credentials of
+// "gcloud auth application-default login" or the metadata server when running on
+// GCE, GKE or Cloud Run.
+func WithDefaultCredentials() Option {
+	return func(o *options) {
+		o.defaultCredentials = true
+	}
+}
+
+// WithMetadataEndpoint authenticates all requests with access tokens of the metadata
+// server at the given address, e.g. "http://169.254.169.254/". Other sources of default
+// credentials are skipped, so it can be used to run against a local stand-in of the
+// metadata server. WithDefaultCredentials finds the metadata server of GCE, GKE or
+// Cloud Run without this option. The tokens are requested with the http client stored
+// in the context under oauth2.HTTPClient, if any.
+func WithMetadataEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.metadataEndpoint = endpoint
+	}
+}
+
+// WithSubject lets the service account act as the given user of a Google Workspace domain,
+// e.g. a shared mailbox. The service account needs domain-wide delegation for the
+// Sheets scopes. It applies to service account keys, given as credentials or
+// through WithDefaultCredentials. Combined with WithHTTPClient, WithTokenSource,
+// WithMetadataEndpoint, WithAPIKey or WithImpersonation the error matches ErrInvalid.
+func WithSubject(email string) Option {
+	return func(o *options) {
+		o.subject = email
+	}
+}
+
+// WithImpersonation accesses the sheets as the given service account, using
+// short-lived tokens of the IAM Service Account Credentials API. The authenticated
+// account needs the role "Service Account Token Creator" on the service account.
+// Delegates form a chain of service accounts from the authenticated account to
+// the impersonated one, where each account may create tokens for the next.
+// The credentials, http client or token source authenticate the requests to the IAM API.
+// The requests to the sheets are sent through the transport of the http client, if given.
+func WithImpersonation(serviceAccount string, delegates ...string) Option {
+	return func(o *options) {
+		o.impersonate = serviceAccount
+		o.delegates = delegates
+	}
+}
+
+// WithIAMEndpoint overrides the base URL of the IAM Service Account Credentials API
+// used by WithImpersonation (default "https://iamcredentials.googleapis.com/").
+func WithIAMEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.iamEndpoint = endpoint
+	}
+}
+
+// WithAPIKey authenticates all requests with an API key instead of credentials.
+// An API key only allows to read spreadsheets which are shared with anyone who has
+// the link, so sheets can only be opened with O_RDONLY. Opening them with O_RDWR
+// or changing them fails with ErrPermission.
+func WithAPIKey(apiKey string) Option {
+	return func(o *options) {
+		o.apiKey = apiKey
+	}
+}
+
+// WithEndpoint overrides the base URL of the Google Sheets API
+// (default "https://sheets.googleapis.com/").
+// Can be used to send requests to a proxy or a local emulator.
+func WithEndpoint(endpoint string) Option {
+	return func(o *options) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithRetry retries requests which failed due to exhausted quotas or
+// transient server errors according to the given policy.
+// By default requests are not retried.
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = &policy
+	}
+}
+
+// WithRateLimiter delays requests until the limiter allows them instead of
+// letting them fail due to exhausted quotas.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *options) {
+		o.rateLimiter = limiter
+	}
+}
+
+// WithOverwrite opens the sheet in overwrite mode. Instead of appending,
+// writes place their data starting at the anchor cell (e.g. "B2") and
+// overwrite existing values. Each write continues below the rows written before.
+func WithOverwrite(anchor string) Option {
+	return func(o *options) {
+		o.overwriteAnchor = anchor
+	}
+}
+
+// WithRange restricts reads, writes and truncation to a region of the sheet.
+// The range is given in A1 notation, e.g. "Data!B2:F500" or "B2:F500" relative
+// to the opened sheet. A name without cells refers to a named range of the
+// spreadsheet. If the sheet name passed to OpenSheet is empty, the sheet of
+// the range is opened.
+func WithRange(a1Range string) Option {
+	return func(o *options) {
+		o.a1Range = a1Range
+	}
+}
+
+// WithValueInputOption sets how the values written to the sheet are
+// interpreted. Use InputUserEntered to write formulas, numbers and dates.
+func WithValueInputOption(option ValueInputOption) Option {
+	return func(o *options) {
+		o.inputOption = option
+	}
+}
+
+// WithValueRenderOption sets how values are returned when reading the sheet.
+// Use RenderUnformatted to read plain numbers or RenderFormula to read formulas.
+func WithValueRenderOption(option ValueRenderOption) Option {
+	return func(o *options) {
+		o.renderOption = option
+	}
+}
+
+// WithDateTimeRenderOption sets how dates and times are returned when reading
+// the sheet with RenderUnformatted or RenderFormula.
+func WithDateTimeRenderOption(option DateTimeRenderOption) Option {
+	return func(o *options) {
+		o.dateTimeOption = option
+	}
+}
+
+// WithPageSize reads the sheet in requests of the given number of rows
+// instead of a single request. Use it for very large sheets whose responses
+// would otherwise be slow or time out.
+func WithPageSize(rows int) Option {
+	return func(o *options) {
+		o.pageSize = rows
+	}
+}
+
+// WithWriteBuffer collects written csv data until it reaches the given size in
+// bytes and sends it in requests of at most this size. This reduces the number
+// of requests when writing many rows, e.g. through a csv.Writer.
+// Buffered data is written by Flush or Close of the sheet.
+func WithWriteBuffer(size int) Option {
+	return func(o *options) {
+		o.writeBufferSize = size
+	}
+}
+
+// WithForce lets RemoveByName remove sheets which contain values
+func WithForce() Option {
+	return func(o *options) {
+		o.force = true
+	}
+}
+
+// WithIndex inserts the copy made by Copy or CopyTo at the zero based index,
+// the following sheets move back. Without this option the copy is added as last sheet.
+// Larger indexes add the copy as last sheet.
+func WithIndex(index int) Option {
+	return func(o *options) {
+		o.index = &index
+	}
+}
+
+// withPublicAccess marks the options of sheets opened by OpenPublicSheet,
+// so sheets derived from them do not call the API without credentials
+func withPublicAccess() Option {
+	return func(o *options) {
+		o.publicAccess = true
+	}
+}
+
+func newOptions(opts []Option) *options {
+	result := &options{}
+	for _, opt := range opts {
+		opt(result)
+	}
+	return result
+}
+
+func (o *options) wrapperOptions() []apiwrapper.Option {
+	result := make([]apiwrapper.Option, 0)
+	if o.endpoint != "" {
+		result = append(result, apiwrapper.WithEndpoint(o.endpoint))
+	}
+	if o.retryPolicy != nil {
+		result = append(result, apiwrapper.WithRetryPolicy(*o.retryPolicy))
+	}
+	if o.rateLimiter != nil {
+		result = append(result, apiwrapper.WithRateLimiter(o.rateLimiter))
+	}
+	if o.renderOption != "" {
+		result = append(result, apiwrapper.WithValueRenderOption(o.renderOption))
+	}
+	if o.dateTimeOption != "" {
+		result = append(result, apiwrapper.WithDateTimeRenderOption(o.dateTimeOption))
+	}
+	if o.publicAccess {
+		result = append(result, apiwrapper.WithPublicAccess(o.endpoint))
+	}
+	return result
+}
+
+// validate checks the options which can be verified without a request
+func (o *options) validate() error {
+	switch o.renderOption {
+	case "", RenderFormatted, RenderUnformatted, RenderFormula:
+	default:
+		return fmt.Errorf("%w: unknown value render option '%s'", ErrInvalid, o.renderOption)
+	}
+	switch o.dateTimeOption {
+	case "", DateTimeSerialNumber, DateTimeFormattedString:
+	default:
+		return fmt.Errorf("%w: unknown date time render option '%s'", ErrInvalid, o.dateTimeOption)
+	}
+	if o.index != nil && *o.index < 0 {
+		return fmt.Errorf("%w: negative index %d", ErrInvalid, *o.index)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package gs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/gs/reader"
+	"github.com/jo-hoe/google-sheets/gs/writer"
+	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
+)
+
+// OpenPublicSheet opens a sheet of a spreadsheet which is shared with anyone who has the link
+// for reading without any credentials. The values are read through the csv export of the
+// spreadsheet, so they are always formatted as displayed in the UI and WithValueRenderOption,
+// WithDateTimeRenderOption, WithRange and WithPageSize are not supported. The sheet has no id, Id returns -1.
+// If the spreadsheet is not public, the error matches ErrPermission.
+// Writes and all calls which need the Sheets API, e.g. Stat, Move or CopyTo, fail with ErrPermission.
+//
+// The export infers a single type per column and returns cells of another type as empty,
+// e.g. a text in a column of numbers. Use WithAPIKey to read sheets with mixed columns.
+//
+// The export is requested from https://docs.google.com/ or the address given by WithEndpoint,
+// through the client given by WithHTTPClient or http.DefaultClient.
+func OpenPublicSheet(ctx context.Context, spreadSheetId string, sheetName string, opts ...Option) (*Sheet, error) {
+	opts = append(opts[:len(opts):len(opts)], withPublicAccess())
+	options := newOptions(opts)
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	if options.a1Range != "" || options.pageSize > 0 || options.renderOption != "" || options.dateTimeOption != "" {
+		return nil, fmt.Errorf("%w: ranges, paging and render options are not supported for public sheets", ErrInvalid)
+	}
+	if sheetName == "" {
+		return nil, fmt.Errorf("%w: empty sheet name", ErrInvalid)
+	}
+	client := options.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	wrapperOptions := options.wrapperOptions()
+	wrapper := apiwrapper.NewSheetsApiWrapper(client, wrapperOptions...)
+	a1Range := a1.Range{Sheet: sheetName}
+
+	// check if the spreadsheet is public
+	stream, err := wrapper.StreamRangeValues(ctx, spreadSheetId, a1Range)
+	if err != nil {
+		return nil, err
+	}
+	stream.Close()
+
+	reader, err := reader.NewRangeReader(client, spreadSheetId, a1Range, wrapperOptions...)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := writer.NewRangeWriter(client, spreadSheetId, a1Range, wrapperOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sheet{
+		id:            -1,
+		sheetName:     sheetName,
+		spreadSheetId: spreadSheetId,
+		a1Range:       a1Range,
+		client:        client,
+		opts:          opts,
+		wrapper:       wrapper,
+		reader:        reader,
+		writer:        writer,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package gs
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs/gstest"
+)
+
+func Test_OpenPublicSheet(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheet name", [][]string{{"a", "b"}, {}, {"c"}})
+	server.SetPublic("spreadSheetId", true)
+
+	sheet, err := OpenPublicSheet(context.Background(), "spreadSheetId", "sheet name", WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	defer sheet.Close()
+	assertEqual(t, int32(-1), sheet.Id())
+
+	csvReader := csv.NewReader(sheet)
+	csvReader.FieldsPerRecord = -1
+	actual, err := csvReader.ReadAll()
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"a", "b"}, {"c"}}, actual)
+
+	csvWriter := csv.NewWriter(sheet)
+	csvWriter.Write([]string{"d"})
+	csvWriter.Flush()
+	if err := csvWriter.Error(); !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v but found %v", ErrPermission, err)
+	}
+}
+
+func Test_OpenPublicSheet_Private(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a"}})
+
+	_, err := OpenPublicSheet(context.Background(), "spreadSheetId", "sheetName", WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v but found %v", ErrPermission, err)
+	}
+}
+
+func Test_OpenPublicSheet_Invalid(t *testing.T) {
+	for _, opts := range [][]Option{{WithRange("A1:B2")}, {WithPageSize(10)}, {WithValueRenderOption(RenderUnformatted)}, {WithDateTimeRenderOption(DateTimeSerialNumber)}} {
+		_, err := OpenPublicSheet(context.Background(), "spreadSheetId", "sheetName", opts...)
+		if !errors.Is(err, ErrInvalid) {
+			t.Errorf("expected %v but found %v", ErrInvalid, err)
+		}
+	}
+}
+
+func Test_OpenSheet_APIKey(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a", "b"}})
+
+	_, err := OpenSheet(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, nil, WithAPIKey("key"), WithEndpoint(server.URL))
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v for a private spreadsheet but found %v", ErrPermission, err)
+	}
+
+	server.SetPublic("spreadSheetId", true)
+	sheet, err := OpenSheet(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, nil, WithAPIKey("key"), WithEndpoint(server.URL))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	actual, err := csv.NewReader(sheet).ReadAll()
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"a", "b"}}, actual)
+
+	_, err = OpenSheet(context.Background(), "spreadSheetId", "sheetName", O_RDWR, nil, WithAPIKey("key"), WithEndpoint(server.URL))
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v but found %v", ErrPermission, err)
+	}
+}
+
+func Test_OpenPublicSheet_API_Calls(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a"}})
+	server.SetPublic("spreadSheetId", true)
+	ctx := context.Background()
+
+	sheet, err := OpenPublicSheet(ctx, "spreadSheetId", "sheetName", WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	_, err = sheet.Stat(ctx)
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v for Stat but found %v", ErrPermission, err)
+	}
+	err = sheet.Move(ctx, 0)
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v for Move but found %v", ErrPermission, err)
+	}
+	_, err = sheet.CopyTo(ctx, "spreadSheetId", "Copy")
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v for CopyTo but found %v", ErrPermission, err)
+	}
+	err = sheet.WriteAll([]struct{ Name string }{{Name: "b"}})
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v for WriteAll but found %v", ErrPermission, err)
+	}
+	assertEqual(t, []string{"sheetName"}, server.SheetNames("spreadSheetId"))
+}
@@ -0,0 +1,204 @@
+package reader
+
+import (
+	"context"
+	"io"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
+)
+
+// Rows iterates over the rows of a sheet. The values are decoded while the
+// response is received, so only the current row is kept in memory.
+// With a page size the sheet is requested in blocks of rows.
+//
+//	rows := sheetReader.Rows(ctx)
+//	defer rows.Close()
+//	for rows.Next() {
+//		record := rows.Row()
+//	}
+//	err := rows.Err()
+type Rows struct {
+	ctx           context.Context
+	wrapper       *apiwrapper.SheetsApiWrapper
+	spreadSheetId string
+	a1Range       a1.Range
+	// number of rows per request, 0 to request the whole range at once
+	pageSize int
+
+	// paging state, rows are one based rows of the sheet
+	started  bool
+	nextRow  int
+	lastRow  int
+	stream   *apiwrapper.ValueStream
+	expected int
+	received int
+
+	// empty rows which are returned once another row with values follows
+	pending   int
+	lookahead []any
+	current   []any
+	err       error
+	closed    bool
+}
+
+func newRows(ctx context.Context, wrapper *apiwrapper.SheetsApiWrapper, spreadSheetId string, a1Range a1.Range, pageSize int) *Rows {
+	return &Rows{
+		ctx:           ctx,
+		wrapper:       wrapper,
+		spreadSheetId: spreadSheetId,
+		a1Range:       a1Range,
+		pageSize:      pageSize,
+	}
+}
+
+// ErrorRows returns an iterator without rows whose Err returns err
+func ErrorRows(err error) *Rows {
+	return &Rows{err: err}
+}
+
+// Next advances to the next row. It returns false after the last row or if
+// an error occurred, see Err.
+func (rows *Rows) Next() bool {
+	if rows.err != nil || rows.closed {
+		return false
+	}
+	if rows.lookahead != nil {
+		if rows.pending > 0 {
+			rows.pending--
+			rows.current = []any{}
+			return true
+		}
+		rows.current = rows.lookahead
+		rows.lookahead = nil
+		return true
+	}
+
+	for {
+		if rows.stream == nil {
+			ok, err := rows.openPage()
+			if err != nil {
+				rows.err = err
+				return false
+			}
+			if !ok {
+				rows.current = nil
+				rows.closed = true
+				return false
+			}
+		}
+
+		row, err := rows.stream.Next()
+		if err == io.EOF {
+			rows.stream.Close()
+			rows.stream = nil
+			// rows at the end of a page are omitted if they are empty
+			if rows.expected > rows.received {
+				rows.pending += rows.expected - rows.received
+			}
+			continue
+		}
+		if err != nil {
+			rows.err = err
+			rows.Close()
+			return false
+		}
+		rows.received++
+		rows.lookahead = row
+		return rows.Next()
+	}
+}
+
+// Values returns the current row with typed cells, which are either string, float64 or bool
+func (rows *Rows) Values() []any {
+	return rows.current
+}
+
+// Row returns the current row formatted like the csv data of SheetReader
+func (rows *Rows) Row() []string {
+	result := make([]string, 0, len(rows.current))
+	for _, value := range rows.current {
+		result = append(result, apiwrapper.FormatValue(value))
+	}
+	return result
+}
+
+// Err returns the error which stopped the iteration, if any
+func (rows *Rows) Err() error {
+	return rows.err
+}
+
+// Close stops the iteration and releases the current response.
+// It is not required to call Close after Next returned false.
+func (rows *Rows) Close() error {
+	rows.closed = true
+	if rows.stream == nil {
+		return nil
+	}
+	err := rows.stream.Close()
+	rows.stream = nil
+	return err
+}
+
+// openPage requests the next page, false is returned after the last page
+func (rows *Rows) openPage() (bool, error) {
+	if rows.pageSize < 1 {
+		if rows.started {
+			return false, nil
+		}
+		rows.started = true
+		return rows.open(rows.a1Range, 0)
+	}
+
+	if !rows.started {
+		rows.started = true
+		if err := rows.initPaging(); err != nil {
+			return false, err
+		}
+	}
+	if rows.nextRow > rows.lastRow {
+		return false, nil
+	}
+	page := rows.a1Range
+	page.Start.Row = rows.nextRow
+	page.End.Row = rows.nextRow + rows.pageSize - 1
+	if page.End.Row > rows.lastRow {
+		page.End.Row = rows.lastRow
+	}
+	rows.nextRow = page.End.Row + 1
+	return rows.open(page, page.End.Row-page.Start.Row+1)
+}
+
+func (rows *Rows) open(a1Range a1.Range, expected int) (bool, error) {
+	stream, err := rows.wrapper.StreamRangeValues(rows.ctx, rows.spreadSheetId, a1Range)
+	if err != nil {
+		return false, err
+	}
+	rows.stream = stream
+	rows.expected = expected
+	rows.received = 0
+	return true, nil
+}
+
+// initPaging determines the rows to request. Without end row the range
+// ends with the last row of the sheet.
+func (rows *Rows) initPaging() error {
+	rows.nextRow = rows.a1Range.StartRow()
+	rows.lastRow = rows.a1Range.End.Row
+	if rows.lastRow != 0 && (rows.a1Range.Start.Column == 0 || rows.a1Range.End.Column != 0) {
+		return nil
+	}
+
+	rowCount, columnCount, err := rows.wrapper.GetGridSize(rows.ctx, rows.spreadSheetId, rows.a1Range.Sheet)
+	if err != nil {
+		return err
+	}
+	if rows.lastRow == 0 {
+		rows.lastRow = rowCount
+	}
+	if rows.a1Range.Start.Column != 0 && rows.a1Range.End.Column == 0 {
+		// a page like "B1:100" is not valid, the end column is required
+		rows.a1Range.End.Column = columnCount
+	}
+	return nil
+}
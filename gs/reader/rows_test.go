@@ -0,0 +1,151 @@
+package reader
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/gs/gstest"
+	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
+)
+
+func readRows(rows *Rows) ([][]string, error) {
+	defer rows.Close()
+	result := make([][]string, 0)
+	for rows.Next() {
+		result = append(result, rows.Row())
+	}
+	return result, rows.Err()
+}
+
+func Test_Rows_Paging(t *testing.T) {
+	values := [][]string{
+		{"a", "b"},
+		{},
+		{},
+		{"c"},
+		{"d", "e"},
+		{},
+		{},
+	}
+	expected := [][]string{
+		{"a", "b"},
+		{},
+		{},
+		{"c"},
+		{"d", "e"},
+	}
+
+	for _, pageSize := range []int{0, 1, 2, 3, 10} {
+		server := gstest.NewServer()
+		server.AddSheet("spreadSheetId", "sheet name", values)
+
+		reader, err := NewSheetReader(server.Client(), "spreadSheetId", "sheet name")
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		reader.SetPageSize(pageSize)
+		actual, err := readRows(reader.Rows(context.Background()))
+		if err != nil {
+			t.Errorf("found error %+v for page size %d", err, pageSize)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected %v but found %v for page size %d", expected, actual, pageSize)
+		}
+		server.Close()
+	}
+}
+
+func Test_Rows_Paging_Range(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheet name", [][]string{
+		{"a", "b", "c"},
+		{"d", "e", "f"},
+		{"g", "h", "i"},
+		{"j", "k", "l"},
+	})
+
+	tests := map[string][][]string{
+		"'sheet name'!B2:C":  {{"e", "f"}, {"h", "i"}, {"k", "l"}},
+		"'sheet name'!B2:B3": {{"e"}, {"h"}},
+		"'sheet name'!B:B":   {{"b"}, {"e"}, {"h"}, {"k"}},
+		"'sheet name'!2:3":   {{"d", "e", "f"}, {"g", "h", "i"}},
+		"'sheet name'!C3":    {{"i"}},
+	}
+	for rangeName, expected := range tests {
+		a1Range, err := a1.ParseRange(rangeName)
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		reader, err := NewRangeReader(server.Client(), "spreadSheetId", a1Range)
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		reader.SetPageSize(1)
+		actual, err := readRows(reader.Rows(context.Background()))
+		if err != nil {
+			t.Errorf("found error %+v for %s", err, rangeName)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected %v but found %v for %s", expected, actual, rangeName)
+		}
+	}
+}
+
+func Test_Rows_Error(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheet name", [][]string{{"a"}, {"b"}, {"c"}})
+
+	reader, err := NewSheetReader(server.Client(), "spreadSheetId", "sheet name")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	reader.SetPageSize(2)
+	rows := reader.Rows(context.Background())
+	defer rows.Close()
+	// the grid size and the first page
+	if !rows.Next() || !rows.Next() {
+		t.Fatalf("expected the rows of the first page, found error %+v", rows.Err())
+	}
+	server.FailRequests(http.StatusBadRequest, 1)
+	if rows.Next() {
+		t.Errorf("expected no further row but found %v", rows.Row())
+	}
+	var apiError *apiwrapper.APIError
+	if !errors.As(rows.Err(), &apiError) {
+		t.Errorf("expected APIError but found %v", rows.Err())
+	}
+}
+
+func Test_SheetReader_Read_Paged(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheet name", [][]string{
+		{"a", "b"},
+		{},
+		{"c", "d,e"},
+	})
+
+	reader, err := NewSheetReader(server.Client(), "spreadSheetId", "sheet name")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	reader.SetPageSize(1)
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	actual, err := csvReader.ReadAll()
+	if err != nil {
+		t.Errorf("found error %+v", err)
+	}
+	// empty rows are not part of csv data
+	expected := [][]string{{"a", "b"}, {"c", "d,e"}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+}
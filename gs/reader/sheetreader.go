@@ -1,35 +1,108 @@
 package reader
 
 import (
+	"bytes"
+	"context"
+	"encoding/csv"
 	"io"
 	"net/http"
 
+	"github.com/jo-hoe/google-sheets/gs/a1"
 	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
 )
 
 type SheetReader struct {
 	io.Reader
-	reader        io.Reader
 	spreadSheetId string
-	sheetName     string
+	a1Range       a1.Range
 	wrapper       *apiwrapper.SheetsApiWrapper
+	pageSize      int
+	// state of Read, rows are converted to csv data on demand
+	rows      *Rows
+	buffer    bytes.Buffer
+	csvWriter *csv.Writer
 }
 
-func NewSheetReader(client *http.Client, spreadSheetId string, sheetName string) (*SheetReader, error) {
+func NewSheetReader(client *http.Client, spreadSheetId string, sheetName string, opts ...apiwrapper.Option) (*SheetReader, error) {
+	return NewRangeReader(client, spreadSheetId, a1.Range{Sheet: sheetName}, opts...)
+}
+
+// NewRangeReader creates a reader which only returns the values of the given range
+func NewRangeReader(client *http.Client, spreadSheetId string, a1Range a1.Range, opts ...apiwrapper.Option) (*SheetReader, error) {
 	return &SheetReader{
-		wrapper:       apiwrapper.NewSheetsApiWrapper(client),
+		wrapper:       apiwrapper.NewSheetsApiWrapper(client, opts...),
 		spreadSheetId: spreadSheetId,
-		sheetName:     sheetName,
+		a1Range:       a1Range,
 	}, nil
 }
 
 func (service *SheetReader) Read(p []byte) (n int, err error) {
-	if service.reader == nil {
-		service.reader, err = service.wrapper.GetSheetData(service.spreadSheetId, service.sheetName)
+	return service.ReadContext(context.Background(), p)
+}
+
+// ReadContext works like Read. The context is used for the requests which fetch the sheet data.
+// Rows are fetched and converted while reading, so the memory usage does not
+// depend on the size of the sheet.
+func (service *SheetReader) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if service.rows == nil {
+		service.rows = service.Rows(ctx)
+		service.csvWriter = csv.NewWriter(&service.buffer)
+	}
+	service.rows.ctx = ctx
+
+	for service.buffer.Len() < len(p) && service.rows.Next() {
+		err = service.csvWriter.Write(service.rows.Row())
 		if err != nil {
-			return -1, err
+			return 0, err
 		}
+		service.csvWriter.Flush()
 	}
+	if service.buffer.Len() == 0 {
+		if err = service.rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, io.EOF
+	}
+	return service.buffer.Read(p)
+}
 
-	return service.reader.Read(p)
+// SetPageSize requests the sheet in blocks of the given number of rows.
+// Smaller responses are less likely to time out for very large sheets.
+// A size lower than 1 requests the whole sheet at once (default).
+func (service *SheetReader) SetPageSize(rows int) {
+	service.pageSize = rows
+}
+
+// Rows returns an iterator over the rows of the sheet. Each call starts a new iteration.
+func (service *SheetReader) Rows(ctx context.Context) *Rows {
+	return newRows(ctx, service.wrapper, service.spreadSheetId, service.a1Range, service.pageSize)
+}
+
+// ReadValues returns the values of the sheet typed according to the render options
+// of the reader. Cells are either string, float64 or bool.
+// In contrast to Read the values are fetched on every call.
+func (service *SheetReader) ReadValues(ctx context.Context) ([][]any, error) {
+	return service.wrapper.GetRangeValues(ctx, service.spreadSheetId, service.a1Range)
+}
+
+// ReadRecords returns the values of the sheet as strings like the csv data returned by Read.
+// In contrast to Read the values are fetched on every call.
+func (service *SheetReader) ReadRecords(ctx context.Context) ([][]string, error) {
+	return service.wrapper.GetRangeRecords(ctx, service.spreadSheetId, service.a1Range)
+}
+
+// ReadHeader returns the first row of the sheet as strings, nil if the sheet is empty.
+// Only the first row is requested.
+func (service *SheetReader) ReadHeader(ctx context.Context) ([]string, error) {
+	headerRange := service.a1Range
+	headerRange.Start.Row = headerRange.StartRow()
+	headerRange.End.Row = headerRange.Start.Row
+	records, err := service.wrapper.GetRangeRecords(ctx, service.spreadSheetId, headerRange)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return records[0], nil
 }
@@ -1,10 +1,16 @@
 package reader
 
 import (
+	"context"
 	"encoding/csv"
+	"io"
+	"net/http"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/jo-hoe/google-sheets/gs/a1"
 	"github.com/jo-hoe/google-sheets/internal/client"
 )
 
@@ -63,3 +69,42 @@ func Test_NewSheetReader_Read_Partial(t *testing.T) {
 		t.Errorf("expected '%v' found '%v'", expected, actual)
 	}
 }
+
+func Test_SheetReader_ReadHeader(t *testing.T) {
+	tests := map[string]string{
+		"sheetName":         "sheetName!1:1",
+		"sheetName!B2:D10":  "sheetName!B2:D2",
+		"sheetName!B:D":     "sheetName!B1:D1",
+		"'sheet name'!C5:E": "'sheet name'!C5:E5",
+		"sheetName!A3":      "sheetName!A3",
+	}
+	for rangeValue, expectedRange := range tests {
+		var requested string
+		mock := client.NewMockClient(func(request *http.Request) *http.Response {
+			requested, _ = url.PathUnescape(strings.TrimPrefix(request.URL.EscapedPath(), "/v4/spreadsheets/spreadSheetId/values/"))
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader(`{"values":[["a","b"]]}`)),
+			}
+		})
+		a1Range, err := a1.ParseRange(rangeValue)
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		reader, err := NewRangeReader(mock, "spreadSheetId", a1Range)
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+
+		actual, err := reader.ReadHeader(context.Background())
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		if !reflect.DeepEqual([]string{"a", "b"}, actual) {
+			t.Errorf("expected '[a b]' found '%v'", actual)
+		}
+		if requested != expectedRange {
+			t.Errorf("expected range '%s' found '%s'", expectedRange, requested)
+		}
+	}
+}
@@ -1,27 +1,279 @@
 package gs
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
 	"io"
+	"net/http"
 
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/gs/codec"
 	"github.com/jo-hoe/google-sheets/gs/reader"
 	"github.com/jo-hoe/google-sheets/gs/writer"
+	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
 )
 
 type Sheet struct {
-	io.ReadWriter
+	io.ReadWriteCloser
+	ctx           context.Context
 	id            int32
 	sheetName     string
 	spreadSheetId string
-	writer        *writer.SheetWriter
-	reader        *reader.SheetReader
+	a1Range       a1.Range
+	// client and options the sheet was opened with
+	client  *http.Client
+	opts    []Option
+	wrapper *apiwrapper.SheetsApiWrapper
+	writer  *writer.SheetWriter
+	reader  *reader.SheetReader
 }
 
+// Write appends csv data to the sheet. Records are written once they are complete,
+// with WithWriteBuffer they are collected until the buffer is full or the sheet is flushed.
+// A last record without line break is only written by Flush or Close.
+// The requests are bound to the context set by WithContext.
 func (service *Sheet) Write(byteData []byte) (n int, err error) {
-	return service.writer.Write(byteData)
+	return service.WriteContext(service.context(), byteData)
 }
 
+// Read reads the sheet as csv data. Buffered writes are flushed first.
+// The requests are bound to the context set by WithContext.
 func (service *Sheet) Read(p []byte) (n int, err error) {
-	return service.reader.Read(p)
+	return service.ReadContext(service.context(), p)
+}
+
+// WriteContext works like Write but binds the requests to the given context.
+func (service *Sheet) WriteContext(ctx context.Context, byteData []byte) (n int, err error) {
+	return service.writer.WriteContext(ctx, byteData)
+}
+
+// ReadContext works like Read but binds the requests to the given context.
+func (service *Sheet) ReadContext(ctx context.Context, p []byte) (n int, err error) {
+	err = service.writer.Flush(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return service.reader.ReadContext(ctx, p)
+}
+
+// Flush writes buffered data to the sheet, including a last record without line break.
+// The requests are bound to the context set by WithContext.
+func (service *Sheet) Flush() error {
+	return service.FlushContext(service.context())
+}
+
+// FlushContext works like Flush but binds the requests to the given context.
+func (service *Sheet) FlushContext(ctx context.Context) error {
+	return service.writer.Flush(ctx)
+}
+
+// Close flushes the sheet. The sheet can still be used afterwards.
+func (service *Sheet) Close() error {
+	return service.Flush()
+}
+
+// OverwriteAt switches the sheet into overwrite mode. The next write places
+// its data starting at the anchor cell (e.g. "B2"), overwriting existing values.
+// Each further write continues below the rows written before, so a region can
+// be refreshed in place by calling OverwriteAt before writing it again.
+// Buffered writes are flushed first.
+func (service *Sheet) OverwriteAt(anchor string) error {
+	err := service.Flush()
+	if err != nil {
+		return err
+	}
+	err = service.writer.OverwriteAt(anchor)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return nil
+}
+
+// ReadValues returns the values of the sheet with their types, e.g. float64 for
+// numbers if the sheet was opened with WithValueRenderOption(RenderUnformatted).
+// Cells are either string, float64 or bool.
+func (service *Sheet) ReadValues(ctx context.Context) ([][]any, error) {
+	err := service.writer.Flush(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.reader.ReadValues(ctx)
+}
+
+// Rows returns an iterator over the rows of the sheet. The rows are decoded
+// while they are received, so large sheets can be processed with little memory.
+//
+//	rows := sheet.Rows(ctx)
+//	defer rows.Close()
+//	for rows.Next() {
+//		record := rows.Row()
+//	}
+//	if err := rows.Err(); err != nil {
+//		// ...
+//	}
+func (service *Sheet) Rows(ctx context.Context) *Rows {
+	err := service.writer.Flush(ctx)
+	if err != nil {
+		return reader.ErrorRows(err)
+	}
+	return service.reader.Rows(ctx)
+}
+
+// ReadAll reads all rows of the sheet into dst, which has to be a pointer to a
+// slice of structs. The first row is the header, its columns are mapped to the
+// struct fields by their `sheet:"Column Name"` tag, see package codec.
+// Dates are formatted by the locale of the spreadsheet unless the sheet is opened with
+// WithValueRenderOption(RenderUnformatted), which returns them as serial numbers
+// that time.Time fields without layout option decode.
+// The requests are bound to the context set by WithContext.
+func (service *Sheet) ReadAll(dst any) error {
+	return service.ReadAllContext(service.context(), dst)
+}
+
+// ReadAllContext works like ReadAll but binds the requests to the given context.
+func (service *Sheet) ReadAllContext(ctx context.Context, dst any) error {
+	records, err := service.readRecords(ctx)
+	if err != nil {
+		return err
+	}
+	return service.decodeRecords(records, dst, service.a1Range.StartRow()-1)
+}
+
+// WriteAll writes src, a slice of structs, as rows to the sheet.
+// The values are placed in the columns of the existing header row.
+// If the sheet is empty, a header row is written first.
+// Use InputUserEntered to store numbers, booleans and dates with their type.
+// The requests are bound to the context set by WithContext.
+func (service *Sheet) WriteAll(src any) error {
+	return service.WriteAllContext(service.context(), src)
+}
+
+// WriteAllContext works like WriteAll but binds the requests to the given context.
+func (service *Sheet) WriteAllContext(ctx context.Context, src any) error {
+	err := service.writer.Flush(ctx)
+	if err != nil {
+		return err
+	}
+	header, err := service.reader.ReadHeader(ctx)
+	if err != nil {
+		return err
+	}
+	writeHeader := len(header) == 0
+	if writeHeader {
+		header, err = codec.Header(src)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+	}
+
+	data, err := codec.Encode(src, header)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if writeHeader {
+		data = append([][]string{header}, data...)
+	}
+	return service.writer.WriteRecords(ctx, data)
+}
+
+// SetInputOption changes how the values of subsequent writes are interpreted.
+// Use InputUserEntered to write formulas, numbers and dates, InputRaw to store text as is.
+// Buffered writes are flushed first.
+func (service *Sheet) SetInputOption(option ValueInputOption) error {
+	if option != InputRaw && option != InputUserEntered {
+		return fmt.Errorf("%w: unknown value input option '%s'", ErrInvalid, option)
+	}
+	err := service.Flush()
+	if err != nil {
+		return err
+	}
+	err = service.writer.SetInputOption(option)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return nil
+}
+
+// WithContext returns a shallow copy of the sheet whose Read and Write calls
+// are bound to the given context. Reader and writer state is shared with the original.
+// This allows cancellation when the sheet is used with e.g. csv.NewReader.
+func (service *Sheet) WithContext(ctx context.Context) *Sheet {
+	if ctx == nil {
+		panic("nil context")
+	}
+	result := *service
+	result.ctx = ctx
+	return &result
+}
+
+// Stat returns a SheetInfo describing the sheet, see package level Stat
+func (service *Sheet) Stat(ctx context.Context) (*SheetInfo, error) {
+	err := service.writer.Flush(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return stat(ctx, service.wrapper, service.spreadSheetId, service.sheetName)
+}
+
+// CopyTo copies the sheet including its values and formatting and opens the copy.
+// The destination may be the spreadsheet of the sheet or another one the account
+// can write to. The copy is added as last sheet of the destination unless WithIndex is given.
+// If the destination already contains a sheet with the name, the error matches ErrExist.
+// Buffered writes are flushed first.
+func (service *Sheet) CopyTo(ctx context.Context, dstSpreadSheetId string, dstSheetName string, opts ...Option) (*Sheet, error) {
+	err := service.writer.Flush(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return copySheet(ctx, service.client, append(service.opts[:len(service.opts):len(service.opts)], opts...), service.spreadSheetId, service.id, dstSpreadSheetId, dstSheetName)
+}
+
+// Move changes the position of the sheet within the spreadsheet.
+// The index is the new position, starting with 0 for the first sheet.
+// Larger indexes move the sheet to the end.
+func (service *Sheet) Move(ctx context.Context, index int) error {
+	if index < 0 {
+		return fmt.Errorf("%w: negative index %d", ErrInvalid, index)
+	}
+	metadata, err := service.wrapper.GetSpreadSheet(ctx, service.spreadSheetId)
+	if err != nil {
+		return err
+	}
+	for _, properties := range metadata.Sheets {
+		if properties.Id != service.id {
+			continue
+		}
+		// the API expects the index before the sheet is moved
+		if index > properties.Index {
+			index++
+		}
+		return service.wrapper.MoveSheet(ctx, service.spreadSheetId, service.id, index)
+	}
+	return fmt.Errorf("%w: sheet '%s'", ErrNotExist, service.sheetName)
+}
+
+// SetHidden hides the tab of the sheet in the UI or shows it again.
+// The sheet can still be read and written while it is hidden.
+// The last visible sheet of a spreadsheet cannot be hidden.
+func (service *Sheet) SetHidden(ctx context.Context, hidden bool) error {
+	return service.wrapper.SetSheetHidden(ctx, service.spreadSheetId, service.id, hidden)
+}
+
+// SetTabColor sets the color of the tab of the sheet, nil removes the color.
+// The transparency of the color is ignored.
+func (service *Sheet) SetTabColor(ctx context.Context, tabColor color.Color) error {
+	return service.wrapper.SetTabColor(ctx, service.spreadSheetId, service.id, tabColor)
+}
+
+// SetFrozen sets the number of rows and columns which stay visible when scrolling,
+// e.g. SetFrozen(ctx, 1, 0) keeps a header row visible.
+func (service *Sheet) SetFrozen(ctx context.Context, rows int, columns int) error {
+	if rows < 0 || columns < 0 {
+		return fmt.Errorf("%w: negative number of frozen rows or columns", ErrInvalid)
+	}
+	return service.wrapper.SetFrozen(ctx, service.spreadSheetId, service.id, rows, columns)
 }
 
 // Returns the ID of the sheet
@@ -38,3 +290,41 @@ func (service *Sheet) SpreadSheetId() string {
 func (service *Sheet) Name() string {
 	return service.sheetName
 }
+
+// Returns the range of the sheet which is read and written.
+// Without WithRange it covers the whole sheet.
+func (service *Sheet) Range() a1.Range {
+	return service.a1Range
+}
+
+// readRecords flushes buffered writes and reads the records of the sheet
+func (service *Sheet) readRecords(ctx context.Context) ([][]string, error) {
+	err := service.writer.Flush(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return service.reader.ReadRecords(ctx)
+}
+
+// decodeRecords decodes records with a header into dst. Cell errors report the position
+// within the sheet, rowOffset is the number of sheet rows above the header.
+func (service *Sheet) decodeRecords(records [][]string, dst any, rowOffset int) error {
+	err := codec.Decode(records, dst)
+	cellError := &CellError{}
+	if errors.As(err, &cellError) {
+		cellError.Row += rowOffset
+		cellError.Column += service.a1Range.StartColumn() - 1
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return nil
+}
+
+func (service *Sheet) context() context.Context {
+	if service.ctx == nil {
+		return context.Background()
+	}
+	return service.ctx
+}
@@ -1,7 +1,16 @@
 package gs
 
 import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"image/color"
+	"io"
+	"net/http"
 	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs/gstest"
+	"github.com/jo-hoe/google-sheets/internal/client"
 )
 
 func TestSheet_Id(t *testing.T) {
@@ -36,3 +45,358 @@ func TestSheet_Name(t *testing.T) {
 		t.Errorf("expected %s actual %s", expected, sheet.Name())
 	}
 }
+
+func TestSheet_WithContext(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"a"}})
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDWR, server.Client())
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = sheet.WithContext(ctx).Read(make([]byte, 16))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected '%v' but found '%v'", context.Canceled, err)
+	}
+}
+
+func TestSheet_WriteContext(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDWR, server.Client())
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = sheet.WriteContext(ctx, []byte("a,b\n"))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected '%v' but found '%v'", context.Canceled, err)
+	}
+	if len(server.Values("spreadSheetId", "sheetName")) != 0 {
+		t.Errorf("expected no data to be written")
+	}
+}
+
+func TestSheet_OverwriteAt(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"title"}, {"old"}, {"old"}})
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDWR, server.Client(), WithOverwrite("A2"))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	for _, value := range []string{"first", "second"} {
+		if err = sheet.OverwriteAt("A2"); err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		if _, err = sheet.Write([]byte(value + "\n" + value + "\n")); err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+	}
+
+	assertEqual(t, [][]string{{"title"}, {"second"}, {"second"}}, server.Values("spreadSheetId", "sheetName"))
+	if err = sheet.OverwriteAt("invalid"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
+
+func TestSheet_Write_Rejected(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDWR, server.Client(), WithRange("A1:B1"), WithOverwrite("A1"))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	if _, err = sheet.Write([]byte("a,b\nc,d\n")); err == nil {
+		t.Error("expected error when writing beyond the range")
+	}
+	// the rejected records are not sent again by later reads
+	actual, err := io.ReadAll(sheet)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, "", string(actual))
+}
+
+func TestSheet_SetInputOption(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+	inputOptions := make([]string, 0)
+	httpClient := server.Client()
+	transport := httpClient.Transport
+	httpClient.Transport = client.RoundTripFunc(func(req *http.Request) *http.Response {
+		if option := req.URL.Query().Get("valueInputOption"); option != "" {
+			inputOptions = append(inputOptions, option)
+		}
+		response, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		return response
+	})
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDWR, httpClient, WithValueInputOption(InputUserEntered))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	if _, err = sheet.Write([]byte("=SUM(1;2)\n")); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if err = sheet.SetInputOption(InputRaw); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if _, err = sheet.Write([]byte("=SUM(1;2)\n")); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	assertEqual(t, []string{"USER_ENTERED", "RAW"}, inputOptions)
+	if err = sheet.SetInputOption("PARSED"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
+
+func TestSheet_ReadValues(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"name", "amount", "paid"}, {"rent", "1234.5", "TRUE"}, {"total", "=SUM(B2)"}})
+
+	tests := map[ValueRenderOption][][]any{
+		RenderFormatted:   {{"name", "amount", "paid"}, {"rent", "1234.5", "TRUE"}, {"total", "=SUM(B2)"}},
+		RenderUnformatted: {{"name", "amount", "paid"}, {"rent", 1234.5, true}, {"total", "=SUM(B2)"}},
+	}
+	for renderOption, expected := range tests {
+		sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, server.Client(), WithValueRenderOption(renderOption))
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		actual, err := sheet.ReadValues(context.Background())
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		assertEqual(t, expected, actual)
+	}
+
+	_, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, server.Client(), WithValueRenderOption("TYPED"))
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
+
+func TestSheet_Rows(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"name", "amount"}, {}, {"rent", "1234.5"}, {"total"}})
+
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, server.Client(),
+		WithPageSize(2), WithValueRenderOption(RenderUnformatted))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	rows := sheet.Rows(context.Background())
+	defer rows.Close()
+	actual := make([][]any, 0)
+	for rows.Next() {
+		actual = append(actual, rows.Values())
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]any{{"name", "amount"}, {}, {"rent", 1234.5}, {"total"}}, actual)
+}
+
+func TestSheet_WriteBuffer(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDWR, server.Client(), WithWriteBuffer(1<<20))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if _, err = sheet.Write([]byte("a,b\nc,d")); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{}, server.Values("spreadSheetId", "sheetName"))
+
+	// reading flushes the buffered data
+	actual, err := csv.NewReader(sheet).ReadAll()
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"a", "b"}, {"c", "d"}}, actual)
+
+	var closer io.Closer = sheet
+	if _, err = sheet.Write([]byte("e,f\n")); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if err = closer.Close(); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"a", "b"}, {"c", "d"}, {"e", "f"}}, server.Values("spreadSheetId", "sheetName"))
+}
+
+func TestSheet_Layout(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	server.AddSheet("spreadSheetId", "A", nil)
+	server.AddSheet("spreadSheetId", "B", nil)
+	ctx := context.Background()
+
+	sheet, err := OpenSheetWithClient(ctx, "spreadSheetId", "Sheet1", O_RDWR, server.Client())
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if err = sheet.Move(ctx, 1); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{"A", "Sheet1", "B"}, server.SheetNames("spreadSheetId"))
+	if err = sheet.Move(ctx, 0); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{"Sheet1", "A", "B"}, server.SheetNames("spreadSheetId"))
+	if err = sheet.Move(ctx, 5); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []string{"A", "B", "Sheet1"}, server.SheetNames("spreadSheetId"))
+	if err = sheet.Move(ctx, -1); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+
+	if err = sheet.SetHidden(ctx, true); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if err = sheet.SetTabColor(ctx, color.RGBA{G: 255, A: 255}); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if err = sheet.SetFrozen(ctx, 1, 2); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if err = sheet.SetFrozen(ctx, -1, 0); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+
+	info, err := sheet.Stat(ctx)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, 2, info.Index())
+	assertEqual(t, true, info.Hidden())
+	assertEqual(t, color.Color(color.NRGBA{G: 255, A: 255}), info.TabColor())
+	assertEqual(t, 1, info.FrozenRowCount())
+	assertEqual(t, 2, info.FrozenColumnCount())
+}
+
+func TestSheet_CopyTo(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Template", [][]string{{"a", "b"}, {"c", "d"}})
+	server.AddSpreadSheet("customerId")
+	ctx := context.Background()
+
+	sheet, err := OpenSheetWithClient(ctx, "spreadSheetId", "Template", O_RDWR, server.Client(), WithRange("B1:B"), WithWriteBuffer(1024))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if _, err = sheet.Write([]byte("e\n")); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	copied, err := sheet.CopyTo(ctx, "customerId", "Customer")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	// the copy contains the buffered data and is opened as a whole
+	assertEqual(t, [][]string{{"a", "b"}, {"c", "d"}, {"", "e"}}, server.Values("customerId", "Customer"))
+	assertEqual(t, "Customer", copied.Range().String())
+}
+
+type testOrder struct {
+	Id     int     `sheet:"Order Id"`
+	Amount float64 `sheet:"Amount"`
+	Note   *string `sheet:"Note"`
+}
+
+func TestSheet_WriteAll_ReadAll(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDWR, server.Client(), WithValueInputOption(InputUserEntered))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	note := "urgent"
+	orders := []testOrder{{Id: 1, Amount: 12.5, Note: &note}, {Id: 2, Amount: 3}}
+
+	// the header is only written once
+	for i := 0; i < 2; i++ {
+		if err = sheet.WriteAll(orders[i : i+1]); err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+	}
+	assertEqual(t, [][]string{{"Order Id", "Amount", "Note"}, {"1", "12.5", "urgent"}, {"2", "3"}}, server.Values("spreadSheetId", "sheetName"))
+
+	actual := make([]testOrder, 0)
+	if err = sheet.ReadAll(&actual); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, orders, actual)
+}
+
+func TestSheet_WriteAll_Existing_Header(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{{"Note", "Comment", "Amount", "Order Id"}})
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDWR, server.Client())
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	if err = sheet.WriteAll([]testOrder{{Id: 1, Amount: 2}}); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	assertEqual(t, [][]string{{"Note", "Comment", "Amount", "Order Id"}, {"", "", "2", "1"}}, server.Values("spreadSheetId", "sheetName"))
+	if err = sheet.WriteAll([]struct{ Missing string }{{"a"}}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
+
+func TestSheet_ReadAll_CellError(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", [][]string{
+		{"title"},
+		{"", "Order Id", "Amount"},
+		{"", "1", "12.5"},
+		{"", "2", "twelve"},
+	})
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "sheetName", O_RDONLY, server.Client(), WithRange("B2:C"))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	err = sheet.ReadAll(&[]testOrder{})
+
+	cellError := &CellError{}
+	if !errors.As(err, &cellError) {
+		t.Fatalf("expected CellError but found %+v", err)
+	}
+	if cellError.Row != 4 || cellError.Column != 3 || cellError.Value != "twelve" {
+		t.Errorf("expected error in C4 but found %+v", cellError)
+	}
+	if err = sheet.ReadAll([]testOrder{}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
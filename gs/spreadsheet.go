@@ -0,0 +1,133 @@
+package gs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
+)
+
+// SpreadSheet is a Google Sheets document containing one or more sheets.
+// Its properties are read when it is opened, use Refresh to update them.
+type SpreadSheet struct {
+	flag     int
+	client   *http.Client
+	opts     []Option
+	wrapper  *apiwrapper.SheetsApiWrapper
+	metadata apiwrapper.SpreadSheetMetadata
+}
+
+// SheetProperties describes a sheet of a spreadsheet: its id, title, position,
+// grid size and whether it is hidden in the UI.
+type SheetProperties = apiwrapper.SheetProperties
+
+// CreateSpreadSheet creates a new spreadsheet with the given title,
+// which contains a single empty sheet named "Sheet1".
+// The spreadsheet is owned by the authenticated account, e.g. the service account.
+//
+// The credentials are ignored if a http client, a token source or default credentials
+// are provided as option.
+func CreateSpreadSheet(ctx context.Context, title string, clientCredentialsJson []byte, opts ...Option) (*SpreadSheet, error) {
+	options := newOptions(opts)
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	client, err := resolveClient(ctx, O_RDWR, clientCredentialsJson, options)
+	if err != nil {
+		return nil, err
+	}
+
+	spreadSheet := newSpreadSheet(O_RDWR, client, opts)
+	spreadSheet.metadata, err = spreadSheet.wrapper.CreateSpreadSheet(ctx, title)
+	if err != nil {
+		return nil, err
+	}
+	return spreadSheet, nil
+}
+
+// OpenSpreadSheet opens an existing spreadsheet with the given flag (O_RDONLY or O_RDWR)
+// and reads its properties. Sheets opened through the spreadsheet share its client.
+// If the spreadsheet does not exist or is not shared with the account, the error
+// matches ErrNotExist or ErrPermission.
+//
+// The credentials are ignored if a http client, a token source or default credentials
+// are provided as option.
+func OpenSpreadSheet(ctx context.Context, spreadSheetId string, flag int, clientCredentialsJson []byte, opts ...Option) (*SpreadSheet, error) {
+	options := newOptions(opts)
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	client, err := resolveClient(ctx, flag, clientCredentialsJson, options)
+	if err != nil {
+		return nil, err
+	}
+
+	spreadSheet := newSpreadSheet(flag, client, opts)
+	spreadSheet.metadata, err = spreadSheet.wrapper.GetSpreadSheet(ctx, spreadSheetId)
+	if err != nil {
+		return nil, err
+	}
+	return spreadSheet, nil
+}
+
+func newSpreadSheet(flag int, client *http.Client, opts []Option) *SpreadSheet {
+	return &SpreadSheet{
+		flag:    flag,
+		client:  client,
+		opts:    opts,
+		wrapper: apiwrapper.NewSheetsApiWrapper(client, newOptions(opts).wrapperOptions()...),
+	}
+}
+
+// OpenSheet opens a sheet of the spreadsheet like the package level OpenSheet.
+// The options of the spreadsheet apply, further options are added to them.
+// A spreadsheet opened with O_RDONLY only allows to open its sheets read-only.
+func (spreadSheet *SpreadSheet) OpenSheet(ctx context.Context, sheetName string, flag int, opts ...Option) (*Sheet, error) {
+	if hasFlag(flag, O_RDWR) && !hasFlag(spreadSheet.flag, O_RDWR) {
+		return nil, fmt.Errorf("%w: spreadsheet '%s' is opened read-only", ErrPermission, spreadSheet.Id())
+	}
+	allOpts := append(append([]Option{}, spreadSheet.opts...), opts...)
+	return openSheetWithClient(ctx, spreadSheet.Id(), sheetName, flag, spreadSheet.client, allOpts...)
+}
+
+// Refresh reads the current properties of the spreadsheet and its sheets
+func (spreadSheet *SpreadSheet) Refresh(ctx context.Context) error {
+	metadata, err := spreadSheet.wrapper.GetSpreadSheet(ctx, spreadSheet.Id())
+	if err != nil {
+		return err
+	}
+	spreadSheet.metadata = metadata
+	return nil
+}
+
+// Returns the ID of the spreadsheet
+func (spreadSheet *SpreadSheet) Id() string {
+	return spreadSheet.metadata.Id
+}
+
+// Returns the URL under which the spreadsheet can be opened in the browser
+func (spreadSheet *SpreadSheet) Url() string {
+	return spreadSheet.metadata.Url
+}
+
+// Returns the title of the spreadsheet
+func (spreadSheet *SpreadSheet) Title() string {
+	return spreadSheet.metadata.Title
+}
+
+// Returns the locale of the spreadsheet, e.g. "en_US".
+// It determines how numbers and dates are formatted and parsed.
+func (spreadSheet *SpreadSheet) Locale() string {
+	return spreadSheet.metadata.Locale
+}
+
+// Returns the time zone of the spreadsheet, e.g. "America/New_York"
+func (spreadSheet *SpreadSheet) TimeZone() string {
+	return spreadSheet.metadata.TimeZone
+}
+
+// Returns the sheets of the spreadsheet ordered by their position
+func (spreadSheet *SpreadSheet) Sheets() []SheetProperties {
+	return append([]SheetProperties{}, spreadSheet.metadata.Sheets...)
+}
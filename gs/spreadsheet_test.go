@@ -0,0 +1,91 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs/gstest"
+)
+
+func Test_CreateSpreadSheet(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+
+	created, err := CreateSpreadSheet(context.Background(), "Budget", nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, "Budget", created.Title())
+	assertEqual(t, "https://docs.google.com/spreadsheets/d/"+created.Id()+"/edit", created.Url())
+	assertEqual(t, []SheetProperties{{Id: 0, Title: "Sheet1", Index: 0, RowCount: 1000, ColumnCount: 26}}, created.Sheets())
+
+	opened, err := OpenSpreadSheet(context.Background(), created.Id(), O_RDONLY, nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, created.Id(), opened.Id())
+	assertEqual(t, "en_US", opened.Locale())
+	assertEqual(t, "Etc/GMT", opened.TimeZone())
+}
+
+func Test_OpenSpreadSheet(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	archiveId := server.AddSheet("spreadSheetId", "Archive", [][]string{{"a", "b"}})
+
+	spreadSheet, err := OpenSpreadSheet(context.Background(), "spreadSheetId", O_RDWR, nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, "spreadSheetId", spreadSheet.Title())
+	assertEqual(t, []SheetProperties{
+		{Id: 0, Title: "Sheet1", Index: 0, RowCount: 1000, ColumnCount: 26},
+		{Id: archiveId, Title: "Archive", Index: 1, RowCount: 1000, ColumnCount: 26},
+	}, spreadSheet.Sheets())
+
+	sheet, err := spreadSheet.OpenSheet(context.Background(), "Data", O_RDWR|O_CREATE)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if _, err = sheet.Write([]byte("1,2\n")); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, [][]string{{"1", "2"}}, server.Values("spreadSheetId", "Data"))
+
+	// the properties are updated on refresh only
+	assertEqual(t, 2, len(spreadSheet.Sheets()))
+	if err = spreadSheet.Refresh(context.Background()); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, 3, len(spreadSheet.Sheets()))
+	assertEqual(t, "Data", spreadSheet.Sheets()[2].Title)
+}
+
+func Test_OpenSpreadSheet_ReadOnly(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+
+	spreadSheet, err := OpenSpreadSheet(context.Background(), "spreadSheetId", O_RDONLY, nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if _, err = spreadSheet.OpenSheet(context.Background(), "Sheet1", O_RDONLY); err != nil {
+		t.Errorf("found error %+v", err)
+	}
+	if _, err = spreadSheet.OpenSheet(context.Background(), "Sheet1", O_RDWR); !errors.Is(err, ErrPermission) {
+		t.Errorf("expected '%v' but found '%v'", ErrPermission, err)
+	}
+}
+
+func Test_OpenSpreadSheet_NotExist(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+
+	_, err := OpenSpreadSheet(context.Background(), "unknown", O_RDONLY, nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+}
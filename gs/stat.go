@@ -0,0 +1,97 @@
+package gs
+
+import (
+	"context"
+	"image/color"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
+)
+
+// SheetInfo describes a sheet as returned by Stat
+type SheetInfo struct {
+	properties SheetProperties
+	dataRange  a1.Range
+}
+
+// Stat returns a SheetInfo describing the named sheet without opening it.
+// If the sheet does not exist, the error matches ErrNotExist.
+// Besides the properties of the sheet its values are read to determine the data range.
+//
+// The credentials are ignored if a http client, a token source or default credentials
+// are provided as option.
+func Stat(ctx context.Context, spreadSheetId string, sheetName string, clientCredentialsJson []byte, opts ...Option) (*SheetInfo, error) {
+	options := newOptions(opts)
+	client, err := resolveClient(ctx, O_RDONLY, clientCredentialsJson, options)
+	if err != nil {
+		return nil, err
+	}
+	wrapper := apiwrapper.NewSheetsApiWrapper(client, options.wrapperOptions()...)
+	return stat(ctx, wrapper, spreadSheetId, sheetName)
+}
+
+func stat(ctx context.Context, wrapper *apiwrapper.SheetsApiWrapper, spreadSheetId string, sheetName string) (*SheetInfo, error) {
+	properties, err := wrapper.GetSheetProperties(ctx, spreadSheetId, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	dataRange, err := wrapper.GetDataRange(ctx, spreadSheetId, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	return &SheetInfo{
+		properties: properties,
+		dataRange:  dataRange,
+	}, nil
+}
+
+// Returns the ID of the sheet
+func (info *SheetInfo) Id() int32 {
+	return info.properties.Id
+}
+
+// Returns the name of the sheet
+func (info *SheetInfo) Name() string {
+	return info.properties.Title
+}
+
+// Returns the position of the sheet within the spreadsheet, starting with 0
+func (info *SheetInfo) Index() int {
+	return info.properties.Index
+}
+
+// Returns the number of rows of the grid, including empty rows
+func (info *SheetInfo) RowCount() int {
+	return info.properties.RowCount
+}
+
+// Returns the number of columns of the grid, including empty columns
+func (info *SheetInfo) ColumnCount() int {
+	return info.properties.ColumnCount
+}
+
+// Returns the number of rows which stay visible when scrolling
+func (info *SheetInfo) FrozenRowCount() int {
+	return info.properties.FrozenRowCount
+}
+
+// Returns the number of columns which stay visible when scrolling
+func (info *SheetInfo) FrozenColumnCount() int {
+	return info.properties.FrozenColumnCount
+}
+
+// Returns whether the sheet is hidden in the UI
+func (info *SheetInfo) Hidden() bool {
+	return info.properties.Hidden
+}
+
+// Returns the color of the tab, nil if the tab has no color
+func (info *SheetInfo) TabColor() color.Color {
+	return info.properties.TabColor
+}
+
+// Returns the smallest range containing all non empty cells.
+// For an empty sheet the range has no cells, see a1.Range.IsWholeSheet.
+func (info *SheetInfo) DataRange() a1.Range {
+	return info.dataRange
+}
@@ -0,0 +1,74 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/gs/gstest"
+)
+
+func Test_Stat(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+	id := server.AddSheet("spreadSheetId", "Data", [][]string{{}, {"", "a", "b"}, {"", "c"}})
+
+	info, err := Stat(context.Background(), "spreadSheetId", "Data", nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, id, info.Id())
+	assertEqual(t, "Data", info.Name())
+	assertEqual(t, 1, info.Index())
+	assertEqual(t, 1000, info.RowCount())
+	assertEqual(t, 26, info.ColumnCount())
+	assertEqual(t, 0, info.FrozenRowCount())
+	assertEqual(t, false, info.Hidden())
+	if info.TabColor() != nil {
+		t.Errorf("expected no tab color but found %v", info.TabColor())
+	}
+	assertEqual(t, "Data!B2:C3", info.DataRange().String())
+
+	info, err = Stat(context.Background(), "spreadSheetId", "Sheet1", nil, WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, a1.Range{Sheet: "Sheet1"}, info.DataRange())
+}
+
+func Test_Stat_NotExist(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSpreadSheet("spreadSheetId")
+
+	_, err := Stat(context.Background(), "spreadSheetId", "Unknown", nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+	_, err = Stat(context.Background(), "unknown", "Sheet1", nil, WithHTTPClient(server.Client()))
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+}
+
+func TestSheet_Stat(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "Data", nil)
+
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "Data", O_RDWR, server.Client(), WithWriteBuffer(1024))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if _, err = sheet.Write([]byte("a,b\nc,d\n")); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	// buffered writes are flushed first
+	info, err := sheet.Stat(context.Background())
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, "Data!A1:B2", info.DataRange().String())
+}
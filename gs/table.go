@@ -0,0 +1,205 @@
+package gs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/gs/codec"
+)
+
+// Table provides keyed access to the rows of a sheet, which are mapped to
+// structs of type T like in ReadAll. Rows are identified by the value of a key
+// column, e.g. an id. If several rows share a key, the first one is used.
+//
+// Each operation reads the current rows of the sheet to locate the key and then
+// changes only the affected row. Concurrent changes by others between these two
+// requests are not detected.
+type Table[T any] struct {
+	sheet     *Sheet
+	keyColumn string
+	// names of the columns mapped to fields of T
+	columns map[string]bool
+}
+
+// tableRows is a snapshot of the rows of a table
+type tableRows struct {
+	records  [][]string
+	header   []string
+	keyIndex int
+	// index of the first record with the given key
+	index map[string]int
+}
+
+// NewTable creates a table for the sheet using the column with the given
+// header as key. The key column has to be mapped to a field of T.
+func NewTable[T any](sheet *Sheet, keyColumn string) (*Table[T], error) {
+	header, err := codec.Header(new(T))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	columns := make(map[string]bool)
+	for _, name := range header {
+		columns[name] = true
+	}
+	if !columns[keyColumn] {
+		return nil, fmt.Errorf("%w: key column '%s' is not mapped to a field of %T", ErrInvalid, keyColumn, *new(T))
+	}
+	return &Table[T]{
+		sheet:     sheet,
+		keyColumn: keyColumn,
+		columns:   columns,
+	}, nil
+}
+
+// List returns all rows of the table
+func (table *Table[T]) List(ctx context.Context) ([]T, error) {
+	result := make([]T, 0)
+	err := table.sheet.ReadAllContext(ctx, &result)
+	return result, err
+}
+
+// Get returns the row with the given key. If no row has the key, an error
+// matching ErrNotExist is returned.
+func (table *Table[T]) Get(ctx context.Context, key string) (T, error) {
+	var result T
+	rows, err := table.load(ctx)
+	if err != nil {
+		return result, err
+	}
+	i, ok := rows.index[key]
+	if !ok {
+		return result, fmt.Errorf("%w: no row with %s '%s'", ErrNotExist, table.keyColumn, key)
+	}
+
+	values := make([]T, 0, 1)
+	// the header is the first row of the decoded records
+	err = table.sheet.decodeRecords([][]string{rows.header, rows.records[i]}, &values, table.sheet.a1Range.StartRow()+i-2)
+	if err != nil {
+		return result, err
+	}
+	return values[0], nil
+}
+
+// Upsert updates the row with the key of value or appends a new row if the
+// key does not exist yet. Only the columns mapped to fields of T are written,
+// other columns keep their values including formulas. If the sheet is empty,
+// the header is written first.
+func (table *Table[T]) Upsert(ctx context.Context, value T) error {
+	rows, err := table.load(ctx)
+	if err != nil {
+		return err
+	}
+	if len(rows.header) == 0 {
+		return table.sheet.WriteAllContext(ctx, []T{value})
+	}
+
+	encoded, err := codec.Encode([]T{value}, rows.header)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	record := encoded[0]
+	key := record[rows.keyIndex]
+	if key == "" {
+		return fmt.Errorf("%w: empty value in key column '%s'", ErrInvalid, table.keyColumn)
+	}
+
+	i, ok := rows.index[key]
+	if !ok {
+		i = len(rows.records)
+	}
+	// each contiguous run of mapped columns is written as a range of its own
+	ranges := make([]a1.Range, 0)
+	data := make([][][]string, 0)
+	for start := 0; start < len(rows.header); start++ {
+		if !table.isMapped(rows.header[start]) {
+			continue
+		}
+		end := start + 1
+		for end < len(rows.header) && table.isMapped(rows.header[end]) {
+			end++
+		}
+		ranges = append(ranges, table.cellRange(i, start, end-start))
+		data = append(data, [][]string{record[start:end]})
+		start = end
+	}
+	return table.sheet.writer.UpdateRanges(ctx, ranges, data)
+}
+
+// Delete removes the row with the given key and shifts the rows below up.
+// If the table covers whole rows of the sheet, the row is deleted from the
+// sheet, otherwise only the cells within the columns of the table are removed.
+// If no row has the key, an error matching ErrNotExist is returned.
+func (table *Table[T]) Delete(ctx context.Context, key string) error {
+	rows, err := table.load(ctx)
+	if err != nil {
+		return err
+	}
+	i, ok := rows.index[key]
+	if !ok {
+		return fmt.Errorf("%w: no row with %s '%s'", ErrNotExist, table.keyColumn, key)
+	}
+
+	sheetRange := table.sheet.a1Range
+	row := sheetRange.StartRow() + i
+	deleted := a1.Range{
+		Sheet: sheetRange.Sheet,
+		Start: a1.Cell{Column: sheetRange.Start.Column, Row: row},
+		End:   a1.Cell{Column: sheetRange.End.Column, Row: row},
+	}
+	return table.sheet.writer.DeleteRows(ctx, table.sheet.id, deleted)
+}
+
+// load reads the rows of the table and indexes them by key
+func (table *Table[T]) load(ctx context.Context) (*tableRows, error) {
+	records, err := table.sheet.readRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &tableRows{
+		records:  records,
+		keyIndex: -1,
+		index:    make(map[string]int),
+	}
+	if len(records) == 0 || len(records[0]) == 0 {
+		return result, nil
+	}
+
+	result.header = records[0]
+	for column, name := range result.header {
+		if strings.TrimSpace(name) == table.keyColumn {
+			result.keyIndex = column
+			break
+		}
+	}
+	if result.keyIndex < 0 {
+		return nil, fmt.Errorf("%w: key column '%s' is missing in header %v", ErrInvalid, table.keyColumn, result.header)
+	}
+	for i, record := range records[1:] {
+		if result.keyIndex >= len(record) || record[result.keyIndex] == "" {
+			continue
+		}
+		if _, exists := result.index[record[result.keyIndex]]; !exists {
+			result.index[record[result.keyIndex]] = i + 1
+		}
+	}
+	return result, nil
+}
+
+// cellRange returns the range of cells within a record, i is the index of the record
+// including the header and column the index of the first cell within the record
+func (table *Table[T]) cellRange(i int, column int, width int) a1.Range {
+	sheetRange := table.sheet.a1Range
+	start := a1.Cell{Column: sheetRange.StartColumn() + column, Row: sheetRange.StartRow() + i}
+	return a1.Range{
+		Sheet: sheetRange.Sheet,
+		Start: start,
+		End:   a1.Cell{Column: start.Column + width - 1, Row: start.Row},
+	}
+}
+
+// isMapped returns true if the column with the given header is mapped to a field of T
+func (table *Table[T]) isMapped(name string) bool {
+	return table.columns[strings.TrimSpace(name)]
+}
@@ -0,0 +1,160 @@
+package gs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs/gstest"
+)
+
+type testItem struct {
+	Id    string  `sheet:"Id"`
+	Price float64 `sheet:"Price"`
+}
+
+func createTestTable(t *testing.T, server *gstest.Server, values [][]string, opts ...Option) *Table[testItem] {
+	server.AddSpreadSheet("spreadSheetId")
+	server.AddSheet("spreadSheetId", "items", values)
+	sheet, err := OpenSheetWithClient(context.Background(), "spreadSheetId", "items", O_RDWR, server.Client(), opts...)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	table, err := NewTable[testItem](sheet, "Id")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	return table
+}
+
+func TestTable_Upsert(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	table := createTestTable(t, server, nil)
+	ctx := context.Background()
+
+	for _, item := range []testItem{{"a", 1}, {"b", 2}, {"a", 3}} {
+		if err := table.Upsert(ctx, item); err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+	}
+
+	assertEqual(t, [][]string{{"Id", "Price"}, {"a", "3"}, {"b", "2"}}, server.Values("spreadSheetId", "items"))
+}
+
+func TestTable_Upsert_Keeps_Unmapped_Columns(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	table := createTestTable(t, server, [][]string{{"Comment", "Id", "Price"}, {"keep", "a", "1"}})
+
+	if err := table.Upsert(context.Background(), testItem{"a", 5}); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	assertEqual(t, [][]string{{"Comment", "Id", "Price"}, {"keep", "a", "5"}}, server.Values("spreadSheetId", "items"))
+	if err := table.Upsert(context.Background(), testItem{}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
+
+func TestTable_Upsert_Keeps_Formulas(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	table := createTestTable(t, server, [][]string{{"Id", "Comment", "Price", "Total"}, {"a", "keep", "1", "=C2*2"}})
+
+	// only the mapped columns are written, so the formula is not replaced by its value
+	if err := table.Upsert(context.Background(), testItem{"a", 5}); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if err := table.Upsert(context.Background(), testItem{"b", 2}); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	expected := [][]string{{"Id", "Comment", "Price", "Total"}, {"a", "keep", "5", "=C2*2"}, {"b", "", "2"}}
+	assertEqual(t, expected, server.Values("spreadSheetId", "items"))
+}
+
+func TestTable_Get_List(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	table := createTestTable(t, server, [][]string{{"Id", "Price"}, {"a", "1"}, {"b", "2"}, {"a", "3"}})
+	ctx := context.Background()
+
+	actual, err := table.Get(ctx, "b")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, testItem{"b", 2}, actual)
+
+	actual, err = table.Get(ctx, "a")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, testItem{"a", 1}, actual)
+
+	if _, err = table.Get(ctx, "c"); !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+
+	items, err := table.List(ctx)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	assertEqual(t, []testItem{{"a", 1}, {"b", 2}, {"a", 3}}, items)
+}
+
+func TestTable_Get_CellError(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	table := createTestTable(t, server, [][]string{{}, {"Id", "Price"}, {"a", "1"}, {"b", "two"}}, WithRange("A2:B"))
+
+	_, err := table.Get(context.Background(), "b")
+
+	cellError := &CellError{}
+	if !errors.As(err, &cellError) {
+		t.Fatalf("expected CellError but found %+v", err)
+	}
+	if cellError.Row != 4 || cellError.Column != 2 {
+		t.Errorf("expected error in B4 but found %+v", cellError)
+	}
+}
+
+func TestTable_Delete(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	table := createTestTable(t, server, [][]string{{"Id", "Price"}, {"a", "1"}, {"b", "2"}, {"c", "3"}})
+
+	if err := table.Delete(context.Background(), "b"); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	assertEqual(t, [][]string{{"Id", "Price"}, {"a", "1"}, {"c", "3"}}, server.Values("spreadSheetId", "items"))
+	if err := table.Delete(context.Background(), "b"); !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected '%v' but found '%v'", ErrNotExist, err)
+	}
+}
+
+func TestTable_Delete_Within_Range(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	table := createTestTable(t, server, [][]string{
+		{"Id", "Price", "", "other"},
+		{"a", "1", "", "x"},
+		{"b", "2", "", "y"},
+	}, WithRange("A1:B"))
+
+	if err := table.Delete(context.Background(), "a"); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	assertEqual(t, [][]string{{"Id", "Price", "", "other"}, {"b", "2", "", "x"}, {"", "", "", "y"}}, server.Values("spreadSheetId", "items"))
+}
+
+func TestNewTable_Invalid_Key(t *testing.T) {
+	if _, err := NewTable[testItem](&Sheet{}, "Unknown"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+	if _, err := NewTable[string](&Sheet{}, "Id"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected '%v' but found '%v'", ErrInvalid, err)
+	}
+}
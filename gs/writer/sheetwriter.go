@@ -2,10 +2,14 @@ package writer
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/jo-hoe/google-sheets/gs/a1"
 	"github.com/jo-hoe/google-sheets/internal/apiwrapper"
 )
 
@@ -13,30 +17,235 @@ type SheetWriter struct {
 	io.Writer
 	wrapper       *apiwrapper.SheetsApiWrapper
 	spreadSheetId string
-	sheetName     string
+	a1Range       a1.Range
+	// position of the next write in overwrite mode, nil in append mode
+	anchor *a1.Cell
+	// maximum size of the csv data sent per request, 0 sends each write at once
+	bufferSize int
+	// csv data of an incomplete record of the last write
+	partial []byte
+	// complete records which are not sent yet and their size in bytes
+	records      [][]string
+	bufferedSize int
 }
 
-func NewSheetWriter(client *http.Client, spreadSheetId string, sheetName string) (*SheetWriter, error) {
-	wrapper := apiwrapper.NewSheetsApiWrapper(client)
+func NewSheetWriter(client *http.Client, spreadSheetId string, sheetName string, opts ...apiwrapper.Option) (*SheetWriter, error) {
+	return NewRangeWriter(client, spreadSheetId, a1.Range{Sheet: sheetName}, opts...)
+}
+
+// NewRangeWriter creates a writer which only writes into the given range.
+// Data is appended after the last row of the table found within the range.
+func NewRangeWriter(client *http.Client, spreadSheetId string, a1Range a1.Range, opts ...apiwrapper.Option) (*SheetWriter, error) {
+	wrapper := apiwrapper.NewSheetsApiWrapper(client, opts...)
 
 	return &SheetWriter{
 		wrapper:       wrapper,
 		spreadSheetId: spreadSheetId,
-		sheetName:     sheetName,
+		a1Range:       a1Range,
 	}, nil
 }
 
+// OverwriteAt switches the writer into overwrite mode.
+// The next write places its data starting at the anchor cell (e.g. "B2"),
+// overwriting existing values. Each further write continues below the rows
+// written before. Cells outside of the written data are left untouched.
+func (service *SheetWriter) OverwriteAt(anchor string) error {
+	cell, err := a1.ParseCell(anchor)
+	if err != nil {
+		return err
+	}
+	service.anchor = &cell
+	return nil
+}
+
+// SetInputOption changes how the values of subsequent writes are interpreted,
+// e.g. apiwrapper.InputUserEntered lets Sheets evaluate formulas and parse numbers and dates.
+func (service *SheetWriter) SetInputOption(option apiwrapper.ValueInputOption) error {
+	if option != apiwrapper.InputRaw && option != apiwrapper.InputUserEntered {
+		return fmt.Errorf("unknown value input option '%s'", option)
+	}
+	apiwrapper.WithValueInputOption(option)(service.wrapper)
+	return nil
+}
+
+// SetBufferSize collects written records until they reach the given size in
+// bytes of csv data and sends them in batches of at most this size.
+// Records which are still buffered are sent by Flush or Close.
+// A size lower than 1 sends the complete records of each write at once (default).
+func (service *SheetWriter) SetBufferSize(size int) {
+	service.bufferSize = size
+}
+
+// Write appends csv data to the sheet. Only complete records are sent,
+// a record split across several writes is sent once its end is written.
+// A last record without line break is only sent by Flush or Close.
 func (service *SheetWriter) Write(byteData []byte) (n int, err error) {
-	csvReader := csv.NewReader(bytes.NewReader(byteData))
-	data, err := csvReader.ReadAll()
+	return service.WriteContext(context.Background(), byteData)
+}
+
+// WriteContext works like Write. The context is used for the request which writes the data.
+func (service *SheetWriter) WriteContext(ctx context.Context, byteData []byte) (n int, err error) {
+	data := append(service.partial[:len(service.partial):len(service.partial)], byteData...)
+	end := completeRecords(data)
+	records, err := parseRecords(data[:end])
 	if err != nil {
 		return 0, err
 	}
+	service.partial = append([]byte(nil), data[end:]...)
+	service.buffer(records)
+	return len(byteData), service.send(ctx, false)
+}
 
-	err = service.wrapper.AppendToSheet(service.spreadSheetId, service.sheetName, data)
+// Flush sends all buffered records including a final record without line break.
+func (service *SheetWriter) Flush(ctx context.Context) error {
+	if len(service.partial) > 0 {
+		records, err := parseRecords(service.partial)
+		if err != nil {
+			return err
+		}
+		service.partial = nil
+		service.buffer(records)
+	}
+	return service.send(ctx, true)
+}
+
+// Close flushes the writer. The writer can still be used afterwards.
+func (service *SheetWriter) Close() error {
+	return service.Flush(context.Background())
+}
+
+// WriteRecords appends the records to the sheet, or overwrites the values at the
+// anchor in overwrite mode. Buffered records are sent first.
+func (service *SheetWriter) WriteRecords(ctx context.Context, records [][]string) error {
+	err := service.send(ctx, true)
 	if err != nil {
-		return 0, err
+		return err
 	}
+	return service.writeBatch(ctx, records)
+}
 
-	return len(byteData), nil
+// UpdateRanges overwrites the values of several ranges within the sheet of the writer
+// with a single request, records[i] is written to ranges[i].
+func (service *SheetWriter) UpdateRanges(ctx context.Context, ranges []a1.Range, records [][][]string) error {
+	return service.wrapper.BatchUpdateValues(ctx, service.spreadSheetId, ranges, records)
+}
+
+// DeleteRows deletes the rows of a range and shifts the rows below up,
+// see apiwrapper.SheetsApiWrapper.DeleteRows
+func (service *SheetWriter) DeleteRows(ctx context.Context, sheetId int32, a1Range a1.Range) error {
+	return service.wrapper.DeleteRows(ctx, service.spreadSheetId, sheetId, a1Range)
+}
+
+func (service *SheetWriter) writeBatch(ctx context.Context, records [][]string) error {
+	if service.anchor != nil {
+		return service.overwrite(ctx, records)
+	}
+	return service.wrapper.AppendToRange(ctx, service.spreadSheetId, service.a1Range, records)
+}
+
+func (service *SheetWriter) buffer(records [][]string) {
+	for _, record := range records {
+		service.records = append(service.records, record)
+		service.bufferedSize += recordSize(record)
+	}
+}
+
+// send writes the buffered records in batches limited by the buffer size.
+// Unless all records are flushed, records which do not fill a batch stay buffered.
+// A batch which failed because the quota was exhausted (429) stays buffered, so it is
+// sent again with the next write or flush. Other failures drop the batch: requests
+// rejected as invalid would fail again and a 5xx status or a timeout may have
+// appended the batch anyway, so sending it again could duplicate rows.
+func (service *SheetWriter) send(ctx context.Context, flush bool) error {
+	for len(service.records) > 0 && (flush || service.bufferedSize >= service.bufferSize) {
+		count, size := 0, 0
+		for count < len(service.records) {
+			next := recordSize(service.records[count])
+			if count > 0 && service.bufferSize > 0 && size+next > service.bufferSize {
+				break
+			}
+			count++
+			size += next
+		}
+
+		err := service.writeBatch(ctx, service.records[:count])
+		if err != nil {
+			if !isQuotaExceeded(err) {
+				service.records = service.records[count:]
+				service.bufferedSize -= size
+			}
+			return err
+		}
+		service.records = service.records[count:]
+		service.bufferedSize -= size
+	}
+	if len(service.records) == 0 {
+		service.records = nil
+	}
+	return nil
+}
+
+func (service *SheetWriter) overwrite(ctx context.Context, data [][]string) error {
+	if len(data) == 0 {
+		return nil
+	}
+	err := service.wrapper.UpdateValues(ctx, service.spreadSheetId, service.overwriteRange(), data)
+	if err != nil {
+		return err
+	}
+	service.anchor.Row += len(data)
+	return nil
+}
+
+// overwriteRange starts at the anchor and ends with the range of the writer, if it is bounded.
+// Writing beyond a bounded range is rejected by the API.
+func (service *SheetWriter) overwriteRange() a1.Range {
+	result := a1.Range{Sheet: service.a1Range.Sheet, Start: *service.anchor, End: *service.anchor}
+	if service.a1Range.End.Column != 0 && service.a1Range.End.Row != 0 {
+		result.End = service.a1Range.End
+	}
+	return result
+}
+
+// isQuotaExceeded returns true if the API rejected a request due to an exhausted
+// quota, so it was not applied and can be sent again later
+func isQuotaExceeded(err error) bool {
+	apiError := &apiwrapper.APIError{}
+	return errors.As(err, &apiError) && apiError.StatusCode == http.StatusTooManyRequests
+}
+
+// completeRecords returns the length of the csv data up to the end of its last complete record
+func completeRecords(data []byte) int {
+	end := 0
+	quoted := false
+	for i, value := range data {
+		switch value {
+		case '"':
+			quoted = !quoted
+		case '\n':
+			if !quoted {
+				end = i + 1
+			}
+		}
+	}
+	return end
+}
+
+func parseRecords(data []byte) ([][]string, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	csvReader := csv.NewReader(bytes.NewReader(data))
+	// the number of fields would otherwise depend on how the data is split into writes
+	csvReader.FieldsPerRecord = -1
+	return csvReader.ReadAll()
+}
+
+// recordSize estimates the size of a record as csv data
+func recordSize(record []string) int {
+	result := 1
+	for _, value := range record {
+		result += len(value) + 1
+	}
+	return result
 }
@@ -1,9 +1,16 @@
 package writer
 
 import (
+	"context"
 	"encoding/csv"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
 	"testing"
 
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/gs/gstest"
 	"github.com/jo-hoe/google-sheets/internal/client"
 )
 
@@ -35,3 +42,241 @@ func TestSheetWriter_Write(t *testing.T) {
 		t.Errorf("Found error %+v", err)
 	}
 }
+
+func TestSheetWriter_Overwrite(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheet name", [][]string{
+		{"a", "b", "c"},
+		{"d", "e", "f"},
+		{"g", "h", "i"},
+	})
+
+	sheetWriter, err := NewSheetWriter(server.Client(), "spreadSheetId", "sheet name")
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	if err = sheetWriter.OverwriteAt("B2"); err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	writer := csv.NewWriter(sheetWriter)
+	writer.Write([]string{"0", "1"})
+	writer.Flush()
+	writer.Write([]string{"2", "3"})
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+
+	expected := [][]string{
+		{"a", "b", "c"},
+		{"d", "0", "1"},
+		{"g", "2", "3"},
+	}
+	actual := server.Values("spreadSheetId", "sheet name")
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+}
+
+func TestSheetWriter_OverwriteAt_Invalid(t *testing.T) {
+	sheetWriter, err := NewSheetWriter(client.CreateMockClient(), "spreadSheetId", "sheetName")
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+
+	err = sheetWriter.OverwriteAt("2B")
+
+	if !errors.Is(err, a1.ErrSyntax) {
+		t.Errorf("expected '%v' but found '%v'", a1.ErrSyntax, err)
+	}
+}
+
+func TestSheetWriter_Overwrite_Bounded_Range(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+	a1Range, err := a1.ParseRange("sheetName!A1:B2")
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+
+	sheetWriter, err := NewRangeWriter(server.Client(), "spreadSheetId", a1Range)
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	err = sheetWriter.OverwriteAt("A1")
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+
+	_, err = sheetWriter.Write([]byte("a,b\nc,d\n"))
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	_, err = sheetWriter.Write([]byte("e,f\n"))
+	if err == nil {
+		t.Error("expected error when writing beyond the range")
+	}
+	// the rejected records are dropped instead of failing every later request
+	if err = sheetWriter.Close(); err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	_, err = sheetWriter.Write([]byte("g,h\n"))
+	if err == nil {
+		t.Error("expected error when writing beyond the range")
+	}
+	expected := [][]string{{"a", "b"}, {"c", "d"}}
+	if actual := server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+}
+
+type countingTransport struct {
+	http.RoundTripper
+	requests int
+}
+
+func (transport *countingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	transport.requests++
+	return transport.RoundTripper.RoundTrip(request)
+}
+
+func createCountingClient(server *gstest.Server) (*http.Client, *countingTransport) {
+	client := *server.Client()
+	transport := &countingTransport{RoundTripper: client.Transport}
+	if transport.RoundTripper == nil {
+		transport.RoundTripper = http.DefaultTransport
+	}
+	client.Transport = transport
+	return &client, transport
+}
+
+func TestSheetWriter_Write_Split_Record(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+
+	sheetWriter, err := NewSheetWriter(server.Client(), "spreadSheetId", "sheetName")
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	for _, data := range []string{"a,b\nc,\"d\n", "e\",f\ng", ",h"} {
+		n, err := sheetWriter.Write([]byte(data))
+		if err != nil {
+			t.Fatalf("Found error %+v", err)
+		}
+		if n != len(data) {
+			t.Errorf("expected %d bytes written but found %d", len(data), n)
+		}
+	}
+	expected := [][]string{{"a", "b"}, {"c", "d\ne", "f"}}
+	if actual := server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+
+	// the last record has no line break and is written on close
+	err = sheetWriter.Close()
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	expected = append(expected, []string{"g", "h"})
+	if actual := server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+}
+
+func TestSheetWriter_Write_Buffered(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+	client, transport := createCountingClient(server)
+
+	sheetWriter, err := NewSheetWriter(client, "spreadSheetId", "sheetName")
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	// each record has a size of 6 bytes, so a batch holds 3 records
+	sheetWriter.SetBufferSize(20)
+	writer := csv.NewWriter(sheetWriter)
+	expected := make([][]string, 0)
+	for i := 0; i < 10; i++ {
+		record := []string{"ab", strconv.Itoa(i)}
+		expected = append(expected, record)
+		writer.Write(record)
+		writer.Flush()
+	}
+	if err = writer.Error(); err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	if transport.requests != 3 {
+		t.Errorf("expected 3 requests but found %d", transport.requests)
+	}
+	err = sheetWriter.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	if transport.requests != 4 {
+		t.Errorf("expected 4 requests but found %d", transport.requests)
+	}
+	if actual := server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+}
+
+func TestSheetWriter_Flush_Failed(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+
+	sheetWriter, err := NewSheetWriter(server.Client(), "spreadSheetId", "sheetName")
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	sheetWriter.SetBufferSize(1000)
+	_, err = sheetWriter.Write([]byte("a,b\n"))
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+
+	server.FailRequests(http.StatusTooManyRequests, 1)
+	if err = sheetWriter.Close(); err == nil {
+		t.Error("expected error")
+	}
+	// the records rejected due to the quota stay buffered and are written by the next flush
+	if err = sheetWriter.Close(); err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	expected := [][]string{{"a", "b"}}
+	if actual := server.Values("spreadSheetId", "sheetName"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+}
+
+func TestSheetWriter_Flush_Failed_Unknown_Result(t *testing.T) {
+	server := gstest.NewServer()
+	defer server.Close()
+	server.AddSheet("spreadSheetId", "sheetName", nil)
+
+	sheetWriter, err := NewSheetWriter(server.Client(), "spreadSheetId", "sheetName")
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	sheetWriter.SetBufferSize(1000)
+	_, err = sheetWriter.Write([]byte("a,b\n"))
+	if err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+
+	// the append may have been applied despite the error, so it is not sent again
+	server.FailRequests(http.StatusInternalServerError, 1)
+	if err = sheetWriter.Close(); err == nil {
+		t.Error("expected error")
+	}
+	if err = sheetWriter.Close(); err != nil {
+		t.Fatalf("Found error %+v", err)
+	}
+	if actual := server.Values("spreadSheetId", "sheetName"); len(actual) != 0 {
+		t.Errorf("expected no values but found %v", actual)
+	}
+}
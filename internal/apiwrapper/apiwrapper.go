@@ -2,15 +2,23 @@ package apiwrapper
 
 import (
 	"bytes"
-	"encoding/csv"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
 )
 
-const baseUrl = "https://sheets.googleapis.com/v4/spreadsheets/%s"
+// DefaultEndpoint is the base URL of the Google Sheets API
+const DefaultEndpoint = "https://sheets.googleapis.com/"
+
+const spreadSheetsUrl = "v4/spreadsheets"
+const baseUrl = spreadSheetsUrl + "/%s"
 
 // url is reverse engineered from:
 // https://github.com/googleapis/google-api-go-client/blob/bc181c33247b7fe3d06d2d7139da0fa06fabbd71/sheets/v4/sheets-gen.go#L14283
@@ -20,18 +28,54 @@ const csvUrlTemplate = baseUrl + "/values/%s?alt=json&prettyPrint=false"
 const updateSheetUrl = baseUrl + ":batchUpdate"
 const clearSheetUrl = baseUrl + "/values/%s:clear"
 const appendSheetUrl = baseUrl + "/values/%s:append"
+const updateValuesUrl = baseUrl + "/values/%s"
+const batchUpdateValuesUrl = baseUrl + "/values:batchUpdate"
+const copySheetUrl = baseUrl + "/sheets/%d:copyTo"
 
 const majorDimension = "ROWS"
 
 // https://developers.google.com/sheets/api/reference/rest/v4/ValueInputOption
-const valueInputOption = "RAW"
+// ValueInputOption determines how written values are interpreted by Google Sheets
+type ValueInputOption string
+
+const (
+	// InputRaw stores values as they are, e.g. "=SUM(A1:A3)" is stored as text
+	InputRaw ValueInputOption = "RAW"
+	// InputUserEntered parses values as if they were typed into the UI,
+	// so formulas are evaluated and numbers and dates are recognized
+	InputUserEntered ValueInputOption = "USER_ENTERED"
+)
 
-type values struct {
-	Values [][]string `json:"values"`
+type spreadSheet struct {
+	SpreadSheetId  string             `json:"spreadsheetId,omitempty"`
+	SpreadSheetUrl string             `json:"spreadsheetUrl,omitempty"`
+	Properties     documentProperties `json:"properties"`
+	Sheets         []sheet            `json:"sheets,omitempty"`
+	NamedRanges    []namedRange       `json:"namedRanges,omitempty"`
 }
 
-type spreadSheet struct {
-	Sheets []sheet `json:"sheets"`
+// documentProperties are the properties of the spreadsheet itself,
+// spreadSheetProperties are the properties of one of its sheets
+type documentProperties struct {
+	Title    string `json:"title,omitempty"`
+	Locale   string `json:"locale,omitempty"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+type namedRange struct {
+	NamedRangeId string    `json:"namedRangeId"`
+	Name         string    `json:"name"`
+	Range        gridRange `json:"range"`
+}
+
+// gridRange uses zero based, half open indexes.
+// Missing end indexes mean the range is unbounded.
+type gridRange struct {
+	SheetId          int32 `json:"sheetId"`
+	StartRowIndex    int   `json:"startRowIndex,omitempty"`
+	EndRowIndex      *int  `json:"endRowIndex,omitempty"`
+	StartColumnIndex int   `json:"startColumnIndex,omitempty"`
+	EndColumnIndex   *int  `json:"endColumnIndex,omitempty"`
 }
 
 type sheet struct {
@@ -39,8 +83,35 @@ type sheet struct {
 }
 
 type spreadSheetProperties struct {
-	SheetID int32  `json:"sheetId,omitempty"`
-	Title   string `json:"title,omitempty"`
+	SheetID        int32          `json:"sheetId,omitempty"`
+	Title          string         `json:"title,omitempty"`
+	Index          int            `json:"index,omitempty"`
+	SheetType      string         `json:"sheetType,omitempty"`
+	Hidden         bool           `json:"hidden,omitempty"`
+	GridProperties gridProperties `json:"gridProperties"`
+	TabColorStyle  *colorStyle    `json:"tabColorStyle,omitempty"`
+	// deprecated by the API in favor of TabColorStyle, but still returned
+	TabColor *rgbColor `json:"tabColor,omitempty"`
+}
+
+type gridProperties struct {
+	RowCount          int `json:"rowCount,omitempty"`
+	ColumnCount       int `json:"columnCount,omitempty"`
+	FrozenRowCount    int `json:"frozenRowCount,omitempty"`
+	FrozenColumnCount int `json:"frozenColumnCount,omitempty"`
+}
+
+type colorStyle struct {
+	RgbColor   *rgbColor `json:"rgbColor,omitempty"`
+	ThemeColor string    `json:"themeColor,omitempty"`
+}
+
+// rgbColor components range from 0 to 1, missing components are 0
+type rgbColor struct {
+	Red   float64  `json:"red,omitempty"`
+	Green float64  `json:"green,omitempty"`
+	Blue  float64  `json:"blue,omitempty"`
+	Alpha *float64 `json:"alpha,omitempty"`
 }
 
 type updateRequest struct {
@@ -51,14 +122,59 @@ type updateRequest struct {
 }
 
 type batchRequest struct {
-	DeleteSheet *deleteSheet `json:"deleteSheet,omitempty"`
-	AddSheet    *addSheet    `json:"addSheet,omitempty"`
+	DeleteSheet     *deleteSheet     `json:"deleteSheet,omitempty"`
+	AddSheet        *addSheet        `json:"addSheet,omitempty"`
+	DeleteDimension *deleteDimension `json:"deleteDimension,omitempty"`
+	DeleteRange     *deleteRange     `json:"deleteRange,omitempty"`
+
+	UpdateSheetProperties *updateSheetProperties `json:"updateSheetProperties,omitempty"`
+	DuplicateSheet        *duplicateSheet        `json:"duplicateSheet,omitempty"`
+}
+
+type duplicateSheet struct {
+	SourceSheetId    int32  `json:"sourceSheetId"`
+	InsertSheetIndex int    `json:"insertSheetIndex"`
+	NewSheetName     string `json:"newSheetName,omitempty"`
+}
+
+type copySheetRequest struct {
+	DestinationSpreadSheetId string `json:"destinationSpreadsheetId"`
+}
+
+// updateSheetProperties changes the properties selected by the comma separated field mask.
+// Selected properties which are missing in the json are reset to their default.
+type updateSheetProperties struct {
+	Properties spreadSheetProperties `json:"properties"`
+	Fields     string                `json:"fields"`
+}
+
+type deleteDimension struct {
+	Range dimensionRange `json:"range"`
+}
+
+// dimensionRange uses zero based, half open indexes
+type dimensionRange struct {
+	SheetId    int32  `json:"sheetId"`
+	Dimension  string `json:"dimension"`
+	StartIndex int    `json:"startIndex"`
+	EndIndex   int    `json:"endIndex"`
+}
+
+type deleteRange struct {
+	Range          gridRange `json:"range"`
+	ShiftDimension string    `json:"shiftDimension"`
 }
 
 type batchResponse struct {
+	Replies            []batchReply       `json:"replies,omitempty"`
 	UpdatedSpreadsheet updatedSpreadsheet `json:"updatedSpreadsheet,omitempty"`
 }
 
+// batchReply holds the response to one of the requests of a batch update
+type batchReply struct {
+	DuplicateSheet *sheet `json:"duplicateSheet,omitempty"`
+}
+
 type updatedSpreadsheet struct {
 	Sheets []sheet `json:"sheets,omitempty"`
 }
@@ -77,24 +193,68 @@ type valueRange struct {
 	Values         [][]string `json:"values"`
 }
 
+type batchUpdateValuesRequest struct {
+	ValueInputOption string       `json:"valueInputOption"`
+	Data             []valueRange `json:"data"`
+}
+
 type SheetsApiWrapper struct {
-	httpClient *http.Client
+	httpClient  *http.Client
+	endpoint    string
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+	inputOption ValueInputOption
+	// render options of reads, empty to use the API defaults
+	renderOption   ValueRenderOption
+	dateTimeOption DateTimeRenderOption
+	// base URL of the csv export if public spreadsheets are read without authentication
+	publicEndpoint string
+	sleep          func(ctx context.Context, duration time.Duration) error
+}
+
+// Option configures a SheetsApiWrapper
+type Option func(*SheetsApiWrapper)
+
+// WithEndpoint overrides the base URL of the API.
+// Can be used to send requests to a proxy or a local emulator.
+func WithEndpoint(endpoint string) Option {
+	return func(wrapper *SheetsApiWrapper) {
+		if !strings.HasSuffix(endpoint, "/") {
+			endpoint = endpoint + "/"
+		}
+		wrapper.endpoint = endpoint
+	}
 }
 
-func NewSheetsApiWrapper(httpClient *http.Client) *SheetsApiWrapper {
-	return &SheetsApiWrapper{
-		httpClient: httpClient,
+// WithValueInputOption sets how values written by AppendToRange and UpdateValues
+// are interpreted (default InputRaw)
+func WithValueInputOption(option ValueInputOption) Option {
+	return func(wrapper *SheetsApiWrapper) {
+		wrapper.inputOption = option
+	}
+}
+
+func NewSheetsApiWrapper(httpClient *http.Client, opts ...Option) *SheetsApiWrapper {
+	wrapper := &SheetsApiWrapper{
+		httpClient:  httpClient,
+		endpoint:    DefaultEndpoint,
+		inputOption: InputRaw,
+		sleep:       sleep,
+	}
+	for _, opt := range opts {
+		opt(wrapper)
 	}
+	return wrapper
 }
 
-func (wrapper SheetsApiWrapper) DeleteSheet(spreadSheetId string, sheetId int32) (err error) {
+func (wrapper SheetsApiWrapper) DeleteSheet(ctx context.Context, spreadSheetId string, sheetId int32) (err error) {
 	body := updateRequest{}
 	body.Request = []batchRequest{{
 		DeleteSheet: &deleteSheet{
 			SheetId: sheetId,
 		}}}
 
-	response, err := wrapper.postSheetRequest(fmt.Sprintf(updateSheetUrl, spreadSheetId), body)
+	response, err := wrapper.postSheetRequest(ctx, wrapper.url(updateSheetUrl, spreadSheetId), body, false)
 	if response != nil {
 		response.Close()
 	}
@@ -106,24 +266,71 @@ func (wrapper SheetsApiWrapper) DeleteSheet(spreadSheetId string, sheetId int32)
 	return nil
 }
 
-func (wrapper SheetsApiWrapper) GetSheetData(spreadSheetId string, sheetName string) (io.Reader, error) {
-	// escape sheet name, since it may contain spaces and other URL incompatible characters
-	encodedSheetName := url.QueryEscape(sheetName)
-	url := fmt.Sprintf(csvUrlTemplate, spreadSheetId, encodedSheetName)
-	resp, err := wrapper.httpClient.Get(url)
+// DeleteRows deletes the rows of a range and shifts the cells below up.
+// If the range covers whole rows, the rows are removed from the sheet (deleteDimension).
+// Otherwise only the cells within the columns of the range are removed (deleteRange).
+func (wrapper SheetsApiWrapper) DeleteRows(ctx context.Context, spreadSheetId string, sheetId int32, a1Range a1.Range) (err error) {
+	if a1Range.End.Row == 0 {
+		return fmt.Errorf("range '%s' has no end row", a1Range.String())
+	}
+
+	request := batchRequest{}
+	if a1Range.Start.Column == 0 && a1Range.End.Column == 0 {
+		request.DeleteDimension = &deleteDimension{
+			Range: dimensionRange{
+				SheetId:    sheetId,
+				Dimension:  majorDimension,
+				StartIndex: a1Range.StartRow() - 1,
+				EndIndex:   a1Range.End.Row,
+			},
+		}
+	} else {
+		request.DeleteRange = &deleteRange{
+			Range:          toGridRange(sheetId, a1Range),
+			ShiftDimension: majorDimension,
+		}
+	}
+	body := updateRequest{}
+	body.Request = []batchRequest{request}
+
+	// not idempotent, a repeated request would delete the following rows
+	response, err := wrapper.postSheetRequest(ctx, wrapper.url(updateSheetUrl, spreadSheetId), body, false)
+	if response != nil {
+		response.Close()
+	}
+	return err
+}
+
+// GetRangeValues returns the values of a range typed as returned by the API.
+// Cells are either string, float64 or bool depending on the render options.
+// Trailing empty rows and cells are omitted.
+func (wrapper SheetsApiWrapper) GetRangeValues(ctx context.Context, spreadSheetId string, a1Range a1.Range) ([][]any, error) {
+	stream, err := wrapper.StreamRangeValues(ctx, spreadSheetId, a1Range)
 	if err != nil {
 		return nil, err
 	}
+	return collectValues(stream)
+}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("could not get sheet from url '%s'\nerror %d: %s", url, resp.StatusCode, resp.Status)
+// GetRangeRecords returns the values of a range formatted as strings like in the csv
+// data of the sheet reader. An empty range results in no records.
+func (wrapper SheetsApiWrapper) GetRangeRecords(ctx context.Context, spreadSheetId string, a1Range a1.Range) ([][]string, error) {
+	values, err := wrapper.GetRangeValues(ctx, spreadSheetId, a1Range)
+	if err != nil {
+		return nil, err
 	}
-
-	defer resp.Body.Close()
-	return truncateExtraneousData(resp.Body)
+	result := make([][]string, 0, len(values))
+	for _, row := range values {
+		record := make([]string, 0, len(row))
+		for _, value := range row {
+			record = append(record, FormatValue(value))
+		}
+		result = append(result, record)
+	}
+	return result, nil
 }
 
-func (wrapper SheetsApiWrapper) CreateSheet(spreadSheetId string, sheetName string) (id int32, err error) {
+func (wrapper SheetsApiWrapper) CreateSheet(ctx context.Context, spreadSheetId string, sheetName string) (id int32, err error) {
 	body := updateRequest{}
 	body.IncludeSpreadsheetInResponse = true
 	body.Request = []batchRequest{{
@@ -133,7 +340,7 @@ func (wrapper SheetsApiWrapper) CreateSheet(spreadSheetId string, sheetName stri
 			},
 		}}}
 
-	response, err := wrapper.postSheetRequest(fmt.Sprintf(updateSheetUrl, spreadSheetId), body)
+	response, err := wrapper.postSheetRequest(ctx, wrapper.url(updateSheetUrl, spreadSheetId), body, false)
 	if err != nil {
 		return -1, err
 	}
@@ -150,19 +357,14 @@ func (wrapper SheetsApiWrapper) CreateSheet(spreadSheetId string, sheetName stri
 // Returns the id of a sheet with a given spreadSheetId and sheetName
 // If the sheet does not exist, the sheetId will be -1 and err will be nil.
 // In case an issue with the API or deserizalization occurs, the error is returned.
-func (wrapper SheetsApiWrapper) GetSheetId(spreadSheetId string, sheetName string) (sheetId int32, err error) {
-	url := fmt.Sprintf(baseUrl, spreadSheetId)
-	resp, err := wrapper.httpClient.Get(url)
-
+func (wrapper SheetsApiWrapper) GetSheetId(ctx context.Context, spreadSheetId string, sheetName string) (sheetId int32, err error) {
+	response, err := wrapper.getSheetRequest(ctx, wrapper.url(baseUrl, spreadSheetId))
 	if err != nil {
 		return -1, err
 	}
-	if resp.StatusCode != 200 {
-		return -1, fmt.Errorf("could not get sheet from url '%s'\nerror %d: %s", url, resp.StatusCode, resp.Status)
-	}
 
 	result := spreadSheet{}
-	err = deserialize[spreadSheet](resp.Body, &result)
+	err = deserialize[spreadSheet](response, &result)
 	if err != nil {
 		return -1, err
 	}
@@ -170,16 +372,21 @@ func (wrapper SheetsApiWrapper) GetSheetId(spreadSheetId string, sheetName strin
 	return wrapper.findSheetIdInResponse(result.Sheets, sheetName)
 }
 
-func (wrapper SheetsApiWrapper) AppendToSheet(spreadSheetId string, sheetName string, data [][]string) (err error) {
+func (wrapper SheetsApiWrapper) AppendToSheet(ctx context.Context, spreadSheetId string, sheetName string, data [][]string) (err error) {
+	return wrapper.AppendToRange(ctx, spreadSheetId, a1.Range{Sheet: sheetName}, data)
+}
+
+// AppendToRange appends data after the last row of the table found in the range
+func (wrapper SheetsApiWrapper) AppendToRange(ctx context.Context, spreadSheetId string, a1Range a1.Range, data [][]string) (err error) {
 	body := valueRange{}
-	body.Range = sheetName
+	body.Range = a1Range.String()
 	body.MajorDimension = majorDimension
 	body.Values = data
 
 	queryParameters := make(map[string]string)
-	queryParameters["valueInputOption"] = valueInputOption
+	queryParameters["valueInputOption"] = string(wrapper.inputOption)
 
-	response, err := wrapper.postSheetRequestQueryParameter(fmt.Sprintf(appendSheetUrl, spreadSheetId, sheetName), body, queryParameters)
+	response, err := wrapper.postSheetRequestQueryParameter(ctx, wrapper.url(appendSheetUrl, spreadSheetId, escapeRange(a1Range)), body, queryParameters, false)
 	if response != nil {
 		response.Close()
 	}
@@ -190,49 +397,205 @@ func (wrapper SheetsApiWrapper) AppendToSheet(spreadSheetId string, sheetName st
 	return nil
 }
 
-// delete all data from a sheet
-func (wrapper SheetsApiWrapper) ClearSheet(spreadSheetId string, sheetName string) (err error) {
-	_, err = wrapper.postSheetRequest(fmt.Sprintf(clearSheetUrl, spreadSheetId, sheetName), nil)
+// UpdateValues writes data into the given range (e.g. "Data!B2") overwriting existing values.
+// If the range only contains a start cell, the data expands from that cell.
+func (wrapper SheetsApiWrapper) UpdateValues(ctx context.Context, spreadSheetId string, a1Range a1.Range, data [][]string) (err error) {
+	body := valueRange{}
+	body.Range = a1Range.String()
+	body.MajorDimension = majorDimension
+	body.Values = data
+
+	queryParameters := make(map[string]string)
+	queryParameters["valueInputOption"] = string(wrapper.inputOption)
+
+	response, err := wrapper.sendJSONRequest(ctx, "PUT", wrapper.url(updateValuesUrl, spreadSheetId, escapeRange(a1Range)), body, queryParameters, true)
+	if response != nil {
+		response.Close()
+	}
+	return err
+}
+
+// BatchUpdateValues writes the data of each range with a single request, overwriting
+// existing values. data[i] is written to ranges[i], cells outside the ranges keep their values.
+func (wrapper SheetsApiWrapper) BatchUpdateValues(ctx context.Context, spreadSheetId string, ranges []a1.Range, data [][][]string) (err error) {
+	if len(ranges) != len(data) {
+		return fmt.Errorf("%d ranges but %d data", len(ranges), len(data))
+	}
+	body := batchUpdateValuesRequest{ValueInputOption: string(wrapper.inputOption)}
+	for i, a1Range := range ranges {
+		body.Data = append(body.Data, valueRange{
+			Range:          a1Range.String(),
+			MajorDimension: majorDimension,
+			Values:         data[i],
+		})
+	}
+
+	response, err := wrapper.postSheetRequest(ctx, wrapper.url(batchUpdateValuesUrl, spreadSheetId), body, true)
+	if response != nil {
+		response.Close()
+	}
 	return err
 }
 
-func (wrapper SheetsApiWrapper) postSheetRequest(url string, body any) (out io.ReadCloser, err error) {
-	return wrapper.postSheetRequestQueryParameter(url, body, map[string]string{})
+// delete all data from a sheet
+func (wrapper SheetsApiWrapper) ClearSheet(ctx context.Context, spreadSheetId string, sheetName string) (err error) {
+	return wrapper.ClearRange(ctx, spreadSheetId, a1.Range{Sheet: sheetName})
 }
 
-func (wrapper SheetsApiWrapper) postSheetRequestQueryParameter(url string, body any, queryParams map[string]string) (out io.ReadCloser, err error) {
-	var request *http.Request
-	if body != nil {
-		request, err = wrapper.createJSONPostRequest(url, body)
-	} else {
-		request, err = http.NewRequest("POST", url, nil)
+// ClearRange deletes all values within a range
+func (wrapper SheetsApiWrapper) ClearRange(ctx context.Context, spreadSheetId string, a1Range a1.Range) (err error) {
+	response, err := wrapper.postSheetRequest(ctx, wrapper.url(clearSheetUrl, spreadSheetId, escapeRange(a1Range)), nil, true)
+	if response != nil {
+		response.Close()
 	}
+	return err
+}
+
+// GetNamedRange resolves a named range of the spreadsheet.
+// If the spreadsheet has no range with the given name, an error matching ErrNotExist is returned.
+func (wrapper SheetsApiWrapper) GetNamedRange(ctx context.Context, spreadSheetId string, name string) (a1.Range, error) {
+	response, err := wrapper.getSheetRequest(ctx, wrapper.url(baseUrl, spreadSheetId))
 	if err != nil {
-		return nil, err
+		return a1.Range{}, err
+	}
+
+	result := spreadSheet{}
+	err = deserialize[spreadSheet](response, &result)
+	if err != nil {
+		return a1.Range{}, err
+	}
+
+	for _, namedRange := range result.NamedRanges {
+		if namedRange.Name != name {
+			continue
+		}
+		for _, sheet := range result.Sheets {
+			if sheet.Properties.SheetID == namedRange.Range.SheetId {
+				return namedRange.Range.toA1(sheet.Properties.Title), nil
+			}
+		}
+	}
+	return a1.Range{}, fmt.Errorf("%w: named range '%s'", ErrNotExist, name)
+}
+
+func (wrapper SheetsApiWrapper) getSheetRequest(ctx context.Context, url string) (out io.ReadCloser, err error) {
+	return wrapper.doRequest(ctx, "GET", url, nil, true)
+}
+
+// postSheetRequest sends a json body to the API.
+// Only idempotent requests are retried after server errors.
+func (wrapper SheetsApiWrapper) postSheetRequest(ctx context.Context, url string, body any, idempotent bool) (out io.ReadCloser, err error) {
+	return wrapper.postSheetRequestQueryParameter(ctx, url, body, map[string]string{}, idempotent)
+}
+
+func (wrapper SheetsApiWrapper) postSheetRequestQueryParameter(ctx context.Context, url string, body any, queryParams map[string]string, idempotent bool) (out io.ReadCloser, err error) {
+	return wrapper.sendJSONRequest(ctx, "POST", url, body, queryParams, idempotent)
+}
+
+func (wrapper SheetsApiWrapper) sendJSONRequest(ctx context.Context, method string, url string, body any, queryParams map[string]string, idempotent bool) (out io.ReadCloser, err error) {
+	var jsonBody []byte
+	if body != nil {
+		jsonBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// add query parameters
 	if len(queryParams) > 0 {
-		query := request.URL.Query()
-		for key, value := range queryParams {
-			query.Add(key, value)
-		}
-		request.URL.RawQuery = query.Encode()
+		url = url + "?" + encodeQuery(queryParams)
 	}
 
-	response, err := wrapper.httpClient.Do(request)
-	if err != nil {
-		return nil, err
+	return wrapper.doRequest(ctx, method, url, jsonBody, idempotent)
+}
+
+// doRequest sends a request and retries it according to the retry policy.
+// The body of a successful response has to be closed by the caller.
+func (wrapper SheetsApiWrapper) doRequest(ctx context.Context, method string, url string, jsonBody []byte, idempotent bool) (out io.ReadCloser, err error) {
+	if wrapper.publicEndpoint != "" {
+		// without credentials only the values of the csv export can be read
+		return nil, fmt.Errorf("%w: public spreadsheets only allow to read values", ErrPermission)
 	}
-	if response.StatusCode != 200 {
-		responseBody, err := io.ReadAll(response.Body)
+	for attempt := 1; ; attempt++ {
+		if err = wrapper.rateLimiter.Wait(ctx, method); err != nil {
+			return nil, err
+		}
+		request, err := wrapper.createRequest(ctx, method, url, jsonBody)
 		if err != nil {
 			return nil, err
 		}
-		return nil, fmt.Errorf("response was '%s': %s", response.Status, string(responseBody))
+
+		response, transportErr := wrapper.httpClient.Do(request)
+		if transportErr != nil {
+			if !wrapper.retryPolicy.shouldRetry(ctx, attempt, 0, transportErr, idempotent) {
+				return nil, transportErr
+			}
+			if err = wrapper.sleep(ctx, wrapper.retryPolicy.backoff(attempt, 0, random())); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if response.StatusCode == 200 {
+			return response.Body, nil
+		}
+
+		retryAfter := parseRetryAfter(response.Header, time.Now())
+		err = newAPIError(url, response)
+		if !wrapper.retryPolicy.shouldRetry(ctx, attempt, response.StatusCode, nil, idempotent) {
+			return nil, err
+		}
+		sleepErr := wrapper.sleep(ctx, wrapper.retryPolicy.backoff(attempt, retryAfter, random()))
+		if sleepErr != nil {
+			return nil, sleepErr
+		}
 	}
+}
 
-	return response.Body, nil
+// escapeRange formats a range for the usage within the url path
+func escapeRange(a1Range a1.Range) string {
+	return url.PathEscape(a1Range.String())
+}
+
+func (r gridRange) toA1(sheetName string) a1.Range {
+	result := a1.Range{Sheet: sheetName}
+	if r.EndRowIndex != nil {
+		result.Start.Row = r.StartRowIndex + 1
+		result.End.Row = *r.EndRowIndex
+	}
+	if r.EndColumnIndex != nil {
+		result.Start.Column = r.StartColumnIndex + 1
+		result.End.Column = *r.EndColumnIndex
+	}
+	if r.EndRowIndex == nil && r.StartRowIndex > 0 {
+		result.Start.Row = r.StartRowIndex + 1
+	}
+	if r.EndColumnIndex == nil && r.StartColumnIndex > 0 {
+		result.Start.Column = r.StartColumnIndex + 1
+	}
+	return result
+}
+
+// toGridRange converts a range into zero based, half open indexes
+func toGridRange(sheetId int32, a1Range a1.Range) gridRange {
+	result := gridRange{
+		SheetId:          sheetId,
+		StartRowIndex:    a1Range.StartRow() - 1,
+		StartColumnIndex: a1Range.StartColumn() - 1,
+	}
+	if a1Range.End.Row != 0 {
+		endRow := a1Range.End.Row
+		result.EndRowIndex = &endRow
+	}
+	if a1Range.End.Column != 0 {
+		endColumn := a1Range.End.Column
+		result.EndColumnIndex = &endColumn
+	}
+	return result
+}
+
+// url builds the absolute url of an api call from a template relative to the endpoint
+func (wrapper SheetsApiWrapper) url(template string, args ...any) string {
+	return wrapper.endpoint + fmt.Sprintf(template, args...)
 }
 
 func (wrapper SheetsApiWrapper) findSheetIdInResponse(allSheets []sheet, sheetName string) (id int32, err error) {
@@ -244,22 +607,27 @@ func (wrapper SheetsApiWrapper) findSheetIdInResponse(allSheets []sheet, sheetNa
 	return -1, nil
 }
 
-func (wrapper SheetsApiWrapper) createJSONPostRequest(url string, body any) (request *http.Request, err error) {
-	jsonBody, err := json.Marshal(body)
-	if err != nil {
-		return nil, err
-	}
-	request, err = http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
-	if body != nil {
-		request.Header.Add("Content-Type", "application/json")
+func (wrapper SheetsApiWrapper) createRequest(ctx context.Context, method string, url string, jsonBody []byte) (request *http.Request, err error) {
+	if jsonBody == nil {
+		return http.NewRequestWithContext(ctx, method, url, nil)
 	}
+	request, err = http.NewRequestWithContext(ctx, method, url, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, err
 	}
+	request.Header.Add("Content-Type", "application/json")
 
 	return request, err
 }
 
+func encodeQuery(queryParams map[string]string) string {
+	query := make(url.Values)
+	for key, value := range queryParams {
+		query.Add(key, value)
+	}
+	return query.Encode()
+}
+
 func deserialize[T any](reader io.ReadCloser, in any) (err error) {
 	defer reader.Close()
 	bytes, err := io.ReadAll(reader)
@@ -273,37 +641,3 @@ func deserialize[T any](reader io.ReadCloser, in any) (err error) {
 	}
 	return nil
 }
-
-// The api returns something like:
-// {"range":"Sheet2!A1:Z1000","majorDimension":"ROWS","values":[["a","b"],["1","2"]]}
-// only the 'values' field is relevant.
-// The returned reader will contain it in an 'encoding/csv' readable format
-func truncateExtraneousData(reader io.ReadCloser) (io.Reader, error) {
-	// not the fastest way to do things but easy to read and maintain
-	result := values{}
-	err := deserialize[values](reader, &result)
-	if err != nil {
-		return nil, err
-	}
-	if result.Values == nil {
-		return nil, fmt.Errorf("could not read 'values' api")
-	}
-
-	// write slices to csv data
-	output := &bytes.Buffer{}
-	writer := csv.NewWriter(output)
-	for _, value := range result.Values {
-		err := writer.Write(value)
-		if err != nil {
-			return nil, err
-		}
-	}
-	writer.Flush()
-	err = writer.Error()
-	if err != nil {
-		return nil, err
-	}
-
-	// return data as reader
-	return bytes.NewReader(output.Bytes()), nil
-}
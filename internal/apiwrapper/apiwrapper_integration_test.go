@@ -14,14 +14,14 @@ func Test_Integration_AppendToSheet(t *testing.T) {
 	wrapper, spreadSheetId := createWrapper(t)
 	sheetName, id := createTestSheet(t, wrapper, spreadSheetId)
 
-	err := wrapper.AppendToSheet(spreadSheetId, sheetName, [][]string{
+	err := wrapper.AppendToSheet(context.Background(), spreadSheetId, sheetName, [][]string{
 		{"0", "1"},
 		{"2", "3"},
 	})
 	if err != nil {
 		t.Errorf("Found error during sheet creation %+v", err)
 	}
-	err = wrapper.AppendToSheet(spreadSheetId, sheetName, [][]string{
+	err = wrapper.AppendToSheet(context.Background(), spreadSheetId, sheetName, [][]string{
 		{"4", "5"},
 		{"6", "7"},
 	})
@@ -37,7 +37,7 @@ func Test_Integration_Create_Get_Delete(t *testing.T) {
 	sheetName := fmt.Sprint(time.Now().UnixMilli() / 1000)
 
 	createdId := createTestSheetWithId(t, wrapper, spreadSheetId, sheetName)
-	foundId, err := wrapper.GetSheetId(spreadSheetId, sheetName)
+	foundId, err := wrapper.GetSheetId(context.Background(), spreadSheetId, sheetName)
 	if err != nil {
 		t.Errorf("Found error during sheet creation %+v", err)
 	}
@@ -54,7 +54,7 @@ func createTestSheet(t *testing.T, wrapper *SheetsApiWrapper, spreadSheetId stri
 }
 
 func createTestSheetWithId(t *testing.T, wrapper *SheetsApiWrapper, spreadSheetId string, sheetName string) int32 {
-	result, err := wrapper.CreateSheet(spreadSheetId, sheetName)
+	result, err := wrapper.CreateSheet(context.Background(), spreadSheetId, sheetName)
 	if err != nil {
 		t.Errorf("Found error during sheet creation %+v", err)
 	}
@@ -62,7 +62,7 @@ func createTestSheetWithId(t *testing.T, wrapper *SheetsApiWrapper, spreadSheetI
 }
 
 func deleteTestSheet(t *testing.T, wrapper *SheetsApiWrapper, spreadSheetId string, id int32) {
-	err := wrapper.DeleteSheet(spreadSheetId, id)
+	err := wrapper.DeleteSheet(context.Background(), spreadSheetId, id)
 	if err != nil {
 		t.Errorf("Found error during sheet creation %+v", err)
 	}
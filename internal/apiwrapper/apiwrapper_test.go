@@ -1,13 +1,15 @@
 package apiwrapper
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"reflect"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/jo-hoe/google-sheets/gs/a1"
 	"github.com/jo-hoe/google-sheets/internal/client"
 )
 
@@ -32,7 +34,7 @@ func Test_GetSheetId(t *testing.T) {
 	}
 	mockClient := client.CreateMockClient(mockResponse)
 	wrappper := NewSheetsApiWrapper(mockClient)
-	actual, err := wrappper.GetSheetId("spreadSheatId", "Sheet2")
+	actual, err := wrappper.GetSheetId(context.Background(), "spreadSheatId", "Sheet2")
 	if err != nil {
 		t.Errorf("found error while reading to buffer %v", err)
 	}
@@ -41,6 +43,28 @@ func Test_GetSheetId(t *testing.T) {
 	}
 }
 
+func Test_WithEndpoint(t *testing.T) {
+	expected := "http://localhost:8080/v4/spreadsheets/spreadSheetId"
+	actual := ""
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		actual = req.URL.String()
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"sheets": []}`)),
+			Header:     make(http.Header),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient, WithEndpoint("http://localhost:8080"))
+
+	_, err := wrapper.GetSheetId(context.Background(), "spreadSheetId", "sheetName")
+	if err != nil {
+		t.Errorf("found error %v", err)
+	}
+	if actual != expected {
+		t.Errorf("expected url '%s' but found '%s'", expected, actual)
+	}
+}
+
 func Test_CreateSheet(t *testing.T) {
 	var expectedId int32 = 2047441944
 	mockResponse := client.ResponseSummery{
@@ -57,7 +81,7 @@ func Test_CreateSheet(t *testing.T) {
 	}
 	mockClient := client.CreateMockClient(mockResponse)
 	wrappper := NewSheetsApiWrapper(mockClient)
-	actual, err := wrappper.CreateSheet("spreadSheetId", "Sheet1")
+	actual, err := wrappper.CreateSheet(context.Background(), "spreadSheetId", "Sheet1")
 	if err != nil {
 		t.Errorf("found error while reading to buffer %v", err)
 	}
@@ -77,7 +101,7 @@ func Test_AppendToSheet(t *testing.T) {
 	}
 	mockClient := client.CreateMockClient(mockResponse, mockResponse)
 	wrappper := NewSheetsApiWrapper(mockClient)
-	err := wrappper.AppendToSheet("spreadSheatId", "spreadSheetName", [][]string{})
+	err := wrappper.AppendToSheet(context.Background(), "spreadSheatId", "spreadSheetName", [][]string{})
 	if err != nil {
 		t.Errorf("found error while reading to buffer %v", err)
 	}
@@ -87,103 +111,207 @@ func Test_AppendToSheet(t *testing.T) {
 	}
 }
 
-func Test_Delete(t *testing.T) {
-	mockResponse := client.ResponseSummery{
-		ResponseCode: 200,
-	}
-	mockClient := client.CreateMockClient(mockResponse)
-	wrappper := NewSheetsApiWrapper(mockClient)
-	err := wrappper.DeleteSheet("spreadSheatId", 1)
+func Test_UpdateValues(t *testing.T) {
+	request := &http.Request{}
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		request = req
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("{}")),
+			Header:     make(http.Header),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
+
+	err := wrapper.UpdateValues(context.Background(), "spreadSheetId", a1.Range{Sheet: "My Sheet", Start: a1.Cell{Column: 2, Row: 2}, End: a1.Cell{Column: 2, Row: 2}}, [][]string{{"0", "1"}})
 	if err != nil {
-		t.Errorf("found error while reading to buffer %v", err)
+		t.Errorf("found error %v", err)
 	}
 
+	if request.Method != "PUT" {
+		t.Errorf("expected method PUT but found %s", request.Method)
+	}
+	expectedPath := "/v4/spreadsheets/spreadSheetId/values/'My Sheet'!B2"
+	if request.URL.Path != expectedPath {
+		t.Errorf("expected path '%s' but found '%s'", expectedPath, request.URL.Path)
+	}
+	if request.URL.Query().Get("valueInputOption") != "RAW" {
+		t.Errorf("expected valueInputOption RAW but found '%s'", request.URL.RawQuery)
+	}
+}
+
+func Test_BatchUpdateValues(t *testing.T) {
+	request := &http.Request{}
+	body := ""
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		request = req
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("{}")),
+			Header:     make(http.Header),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
+
+	ranges := []a1.Range{
+		{Sheet: "sheetName", Start: a1.Cell{Column: 1, Row: 2}, End: a1.Cell{Column: 1, Row: 2}},
+		{Sheet: "sheetName", Start: a1.Cell{Column: 3, Row: 2}, End: a1.Cell{Column: 4, Row: 2}},
+	}
+	err := wrapper.BatchUpdateValues(context.Background(), "spreadSheetId", ranges, [][][]string{{{"a"}}, {{"c", "d"}}})
 	if err != nil {
-		t.Error("expected no error but found", err)
+		t.Errorf("found error %v", err)
+	}
+
+	if request.Method != "POST" {
+		t.Errorf("expected method POST but found %s", request.Method)
+	}
+	expectedPath := "/v4/spreadsheets/spreadSheetId/values:batchUpdate"
+	if request.URL.Path != expectedPath {
+		t.Errorf("expected path '%s' but found '%s'", expectedPath, request.URL.Path)
+	}
+	expectedBody := `{"valueInputOption":"RAW","data":[{"range":"sheetName!A2","majorDimension":"ROWS","values":[["a"]]},{"range":"sheetName!C2:D2","majorDimension":"ROWS","values":[["c","d"]]}]}`
+	if body != expectedBody {
+		t.Errorf("expected body '%s' but found '%s'", expectedBody, body)
+	}
+
+	err = wrapper.BatchUpdateValues(context.Background(), "spreadSheetId", ranges, nil)
+	if err == nil {
+		t.Error("expected error for missing data")
 	}
 }
 
-func Test_GetSheetData(t *testing.T) {
-	mockResponse := client.ResponseSummery{
-		ResponseCode: 200,
-		ResponseBody: "{\"range\":\"Sheet2!A1:Z1000\",\"majorDimension\":\"ROWS\",\"values\":[[\"0\",\"1\"],[\"2\",\"3\"]]}",
+func Test_AppendToSheet_UserEntered(t *testing.T) {
+	request := &http.Request{}
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		request = req
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("{}")),
+			Header:     make(http.Header),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient, WithValueInputOption(InputUserEntered))
+
+	err := wrapper.AppendToSheet(context.Background(), "spreadSheetId", "sheetName", [][]string{{"=SUM(A1:A3)"}})
+	if err != nil {
+		t.Errorf("found error %v", err)
 	}
-	wrappper := NewSheetsApiWrapper(client.CreateMockClient(mockResponse))
 
-	actual, err := wrappper.GetSheetData("spreadSheatId", "sheetName")
+	if request.URL.Query().Get("valueInputOption") != "USER_ENTERED" {
+		t.Errorf("expected valueInputOption USER_ENTERED but found '%s'", request.URL.RawQuery)
+	}
+}
+
+func Test_GetRangeValues_Path(t *testing.T) {
+	request := &http.Request{}
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		request = req
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"range":"Data!B2:C3","values":[["a","b"]]}`)),
+			Header:     make(http.Header),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
+	a1Range, err := a1.ParseRange("'Data 1'!B2:C3")
 	if err != nil {
-		t.Errorf("error found during http reqest %v", err)
+		t.Fatalf("found error %v", err)
 	}
 
-	buf := new(bytes.Buffer)
-	_, err = buf.ReadFrom(actual)
+	_, err = wrapper.GetRangeValues(context.Background(), "spreadSheetId", a1Range)
 	if err != nil {
 		t.Errorf("found error %v", err)
 	}
-	stringActual := buf.String()
-	expected := "0,1\n2,3\n"
 
-	if !reflect.DeepEqual(expected, stringActual) {
-		t.Errorf("expected '%v' found '%v'", expected, stringActual)
+	expectedPath := "/v4/spreadsheets/spreadSheetId/values/'Data 1'!B2:C3"
+	if request.URL.Path != expectedPath {
+		t.Errorf("expected path '%s' but found '%s'", expectedPath, request.URL.Path)
 	}
 }
 
-func Test_truncateExtraneousData(t *testing.T) {
-	type args struct {
-		reader io.ReadCloser
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    string
-		wantErr bool
-	}{
-		{
-			name:    "Positive Test",
-			wantErr: false,
-			args: args{
-				reader: io.NopCloser(strings.NewReader("{\"range\":\"Sheet2!A1:Z1000\",\"majorDimension\":\"ROWS\",\"values\":[[\"a\",\"b\"],[\"1\",\"2\"]]}")),
-			},
-			want: "a,b\n1,2\n",
-		}, {
-			name:    "Not readable values",
-			wantErr: true,
-			args: args{
-				reader: io.NopCloser(strings.NewReader("{\"range\":\"Sheet2!A1:Z1000\",\"majorDimension\":\"ROWS\",\"[not readable]}")),
-			},
-		}, {
-			name:    "Read empty",
-			wantErr: false,
-			args: args{
-				reader: io.NopCloser(strings.NewReader("{\"range\":\"Sheet2!A1:Z1000\",\"majorDimension\":\"ROWS\",\"values\":[]}")),
-			},
-			want: "",
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := truncateExtraneousData(tt.args.reader)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("truncateExtraneousData() error = %v, wantErr %v", err, tt.wantErr)
-			}
+func Test_GetNamedRange(t *testing.T) {
+	body := `{
+			"sheets": [
+				{"properties": {"sheetId": 0, "title": "Sheet1"}},
+				{"properties": {"sheetId": 7, "title": "Data"}}
+			],
+			"namedRanges": [
+				{"namedRangeId": "a", "name": "Block", "range": {"sheetId": 7, "startRowIndex": 1, "endRowIndex": 5, "startColumnIndex": 1, "endColumnIndex": 3}},
+				{"namedRangeId": "b", "name": "Columns", "range": {"sheetId": 7, "startColumnIndex": 0, "endColumnIndex": 2}}
+			]
+		}`
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
 
-			if err != nil {
-				// check if nil is returned in error case
-				if got != nil {
-					t.Errorf("expected nil got %v", got)
-				}
-			} else {
-				// check if string in reader is expected
-				buffer := new(bytes.Buffer)
-				_, err = buffer.ReadFrom(got)
-				stringOutput := buffer.String()
-				if err != nil {
-					t.Errorf("found error while reading to buffer %v", err)
-				}
-				if stringOutput != tt.want {
-					t.Errorf("truncateExtraneousData() = %v, want %v", stringOutput, tt.want)
-				}
+	tests := map[string]string{"Block": "Data!B2:C5", "Columns": "Data!A:B"}
+	for name, expected := range tests {
+		actual, err := wrapper.GetNamedRange(context.Background(), "spreadSheetId", name)
+		if err != nil {
+			t.Errorf("found error %v", err)
+		}
+		if actual.String() != expected {
+			t.Errorf("expected %s for %s but found %s", expected, name, actual.String())
+		}
+	}
+
+	_, err := wrapper.GetNamedRange(context.Background(), "spreadSheetId", "Unknown")
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected ErrNotExist but found %v", err)
+	}
+}
+
+func Test_DeleteRows(t *testing.T) {
+	tests := map[string]string{
+		"Data!3:4":   `{"requests":[{"deleteDimension":{"range":{"sheetId":5,"dimension":"ROWS","startIndex":2,"endIndex":4}}}],"includeSpreadsheetInResponse":false,"responseIncludeGridData":false}`,
+		"Data!B3:C3": `{"requests":[{"deleteRange":{"range":{"sheetId":5,"startRowIndex":2,"endRowIndex":3,"startColumnIndex":1,"endColumnIndex":3},"shiftDimension":"ROWS"}}],"includeSpreadsheetInResponse":false,"responseIncludeGridData":false}`,
+	}
+	for rangeName, expected := range tests {
+		body := ""
+		mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+			data, _ := io.ReadAll(req.Body)
+			body = string(data)
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader("{}")),
+				Header:     make(http.Header),
 			}
 		})
+		wrapper := NewSheetsApiWrapper(mockClient)
+		a1Range, err := a1.ParseRange(rangeName)
+		if err != nil {
+			t.Fatalf("found error %v", err)
+		}
+
+		err = wrapper.DeleteRows(context.Background(), "spreadSheetId", 5, a1Range)
+		if err != nil {
+			t.Errorf("found error %v", err)
+		}
+		if body != expected {
+			t.Errorf("expected body %s for %s but found %s", expected, rangeName, body)
+		}
+	}
+}
+
+func Test_Delete(t *testing.T) {
+	mockResponse := client.ResponseSummery{
+		ResponseCode: 200,
+	}
+	mockClient := client.CreateMockClient(mockResponse)
+	wrappper := NewSheetsApiWrapper(mockClient)
+	err := wrappper.DeleteSheet(context.Background(), "spreadSheatId", 1)
+	if err != nil {
+		t.Errorf("found error while reading to buffer %v", err)
+	}
+
+	if err != nil {
+		t.Error("expected no error but found", err)
 	}
 }
@@ -0,0 +1,90 @@
+package apiwrapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrNotExist   = errors.New("sheet does not exist") // "file does not exist"
+	ErrPermission = errors.New("permission denied")    // "permission denied"
+)
+
+// APIError is returned if the API responds with a status other than 200.
+//
+// It can be compared with errors.Is. A status 404 or a request referring to
+// an unknown sheet id matches ErrNotExist and a status 403 matches ErrPermission.
+type APIError struct {
+	// http status code of the response e.g. 403
+	StatusCode int
+	// error.code of Google's error payload
+	Code int
+	// error.status of Google's error payload e.g. "PERMISSION_DENIED"
+	Status string
+	// error.message of Google's error payload or the raw response body if it
+	// does not contain Google's error payload
+	Message string
+	// error.details of Google's error payload, see
+	// https://cloud.google.com/apis/design/errors#error_details
+	Details []json.RawMessage
+	// url of the failed request
+	URL string
+}
+
+type errorResponse struct {
+	Error struct {
+		Code    int               `json:"code"`
+		Message string            `json:"message"`
+		Status  string            `json:"status"`
+		Details []json.RawMessage `json:"details"`
+	} `json:"error"`
+}
+
+func (apiError *APIError) Error() string {
+	status := apiError.Status
+	if status == "" {
+		status = http.StatusText(apiError.StatusCode)
+	}
+	return fmt.Sprintf("request to '%s' failed with %d %s: %s", apiError.URL, apiError.StatusCode, status, apiError.Message)
+}
+
+func (apiError *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotExist:
+		// batch updates referring to an unknown sheet id fail with 400 "No grid with id: 123"
+		return apiError.StatusCode == http.StatusNotFound ||
+			apiError.StatusCode == http.StatusBadRequest && strings.Contains(apiError.Message, "No grid with id")
+	case ErrPermission:
+		return apiError.StatusCode == http.StatusForbidden
+	default:
+		return false
+	}
+}
+
+// newAPIError reads the body of a failed response and closes it
+func newAPIError(url string, response *http.Response) error {
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	result := &APIError{
+		StatusCode: response.StatusCode,
+		URL:        url,
+	}
+	payload := errorResponse{}
+	if json.Unmarshal(body, &payload) == nil && payload.Error.Code != 0 {
+		result.Code = payload.Error.Code
+		result.Status = payload.Error.Status
+		result.Message = payload.Error.Message
+		result.Details = payload.Error.Details
+	} else {
+		result.Message = strings.TrimSpace(string(body))
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package apiwrapper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/internal/client"
+)
+
+func Test_APIError_Google_Payload(t *testing.T) {
+	mockClient := client.CreateMockClient(client.ResponseSummery{
+		ResponseCode: 403,
+		ResponseBody: `{
+			"error": {
+				"code": 403,
+				"message": "The caller does not have permission",
+				"status": "PERMISSION_DENIED",
+				"details": [{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "ACCESS_TOKEN_SCOPE_INSUFFICIENT"}]
+			}
+		}`,
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
+
+	_, err := wrapper.GetSheetId(context.Background(), "spreadSheetId", "sheetName")
+
+	apiError := &APIError{}
+	if !errors.As(err, &apiError) {
+		t.Fatalf("expected APIError but found %v", err)
+	}
+	if apiError.StatusCode != 403 || apiError.Code != 403 {
+		t.Errorf("expected status 403 but found %d and code %d", apiError.StatusCode, apiError.Code)
+	}
+	if apiError.Status != "PERMISSION_DENIED" {
+		t.Errorf("expected status PERMISSION_DENIED but found %s", apiError.Status)
+	}
+	if apiError.Message != "The caller does not have permission" {
+		t.Errorf("unexpected message %s", apiError.Message)
+	}
+	if len(apiError.Details) != 1 {
+		t.Errorf("expected 1 detail but found %d", len(apiError.Details))
+	}
+	if apiError.URL != "https://sheets.googleapis.com/v4/spreadsheets/spreadSheetId" {
+		t.Errorf("unexpected url %s", apiError.URL)
+	}
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected error to match %v", ErrPermission)
+	}
+	if errors.Is(err, ErrNotExist) {
+		t.Errorf("expected error not to match %v", ErrNotExist)
+	}
+}
+
+func Test_APIError_Not_Found(t *testing.T) {
+	mockClient := client.CreateMockClient(client.ResponseSummery{
+		ResponseCode: 404,
+		ResponseBody: `{"error": {"code": 404, "message": "Requested entity was not found.", "status": "NOT_FOUND"}}`,
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
+
+	err := wrapper.DeleteSheet(context.Background(), "spreadSheetId", 1)
+
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected error to match %v but found %v", ErrNotExist, err)
+	}
+}
+
+func Test_APIError_Unknown_Sheet(t *testing.T) {
+	mockClient := client.CreateMockClient(client.ResponseSummery{
+		ResponseCode: 400,
+		ResponseBody: `{"error": {"code": 400, "message": "Invalid requests[0].deleteSheet: No grid with id: 1", "status": "INVALID_ARGUMENT"}}`,
+	}, client.ResponseSummery{
+		ResponseCode: 400,
+		ResponseBody: `{"error": {"code": 400, "message": "Invalid requests[0].deleteSheet: You can't remove all the sheets in a document.", "status": "INVALID_ARGUMENT"}}`,
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
+
+	err := wrapper.DeleteSheet(context.Background(), "spreadSheetId", 1)
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected error to match %v but found %v", ErrNotExist, err)
+	}
+	err = wrapper.DeleteSheet(context.Background(), "spreadSheetId", 1)
+	if err == nil || errors.Is(err, ErrNotExist) {
+		t.Errorf("expected error not to match %v but found %v", ErrNotExist, err)
+	}
+}
+
+func Test_APIError_Plain_Body(t *testing.T) {
+	mockClient := client.CreateMockClient(client.ResponseSummery{
+		ResponseCode: 502,
+		ResponseBody: "Bad Gateway\n",
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
+
+	err := wrapper.ClearSheet(context.Background(), "spreadSheetId", "sheetName")
+
+	apiError := &APIError{}
+	if !errors.As(err, &apiError) {
+		t.Fatalf("expected APIError but found %v", err)
+	}
+	if apiError.Message != "Bad Gateway" || apiError.Status != "" {
+		t.Errorf("unexpected error %+v", apiError)
+	}
+}
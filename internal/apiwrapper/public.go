@@ -0,0 +1,139 @@
+package apiwrapper
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+)
+
+// DefaultPublicEndpoint is the base URL of the csv export of public spreadsheets
+const DefaultPublicEndpoint = "https://docs.google.com/"
+
+// publicCsvUrl exports a sheet as csv through the Google Visualization API.
+// headers=0 prevents the export from merging leading rows into a header row.
+// The export infers a single type per column from its values and returns cells of
+// other types as empty, e.g. text in a column of numbers. The export by gid
+// (export?format=csv&gid=) keeps all cells, but requires the sheet id, which
+// cannot be looked up without credentials.
+const publicCsvUrl = "spreadsheets/d/%s/gviz/tq?tqx=out:csv&headers=0&sheet=%s"
+
+// WithPublicAccess reads the values of spreadsheets which are shared with anyone who has
+// the link through their csv export, which requires no authentication. All other
+// requests, e.g. for the metadata or to change a spreadsheet, fail with ErrPermission.
+// The values are always formatted as displayed in the UI and cells whose type differs
+// from the other cells of their column are empty. An empty endpoint uses DefaultPublicEndpoint.
+func WithPublicAccess(endpoint string) Option {
+	return func(wrapper *SheetsApiWrapper) {
+		if endpoint == "" {
+			endpoint = DefaultPublicEndpoint
+		}
+		if !strings.HasSuffix(endpoint, "/") {
+			endpoint = endpoint + "/"
+		}
+		wrapper.publicEndpoint = endpoint
+	}
+}
+
+// streamPublicValues requests the csv export of a sheet and returns a stream of its rows.
+// Only whole sheets can be exported.
+func (wrapper SheetsApiWrapper) streamPublicValues(ctx context.Context, spreadSheetId string, a1Range a1.Range) (*ValueStream, error) {
+	if !a1Range.IsWholeSheet() {
+		return nil, fmt.Errorf("%w: range '%s' cannot be read from a public spreadsheet, only whole sheets", ErrPermission, a1Range.String())
+	}
+	requestUrl := wrapper.publicEndpoint + fmt.Sprintf(publicCsvUrl, url.PathEscape(spreadSheetId), url.QueryEscape(a1Range.Sheet))
+	if err := wrapper.rateLimiter.Wait(ctx, "GET"); err != nil {
+		return nil, err
+	}
+	request, err := wrapper.createRequest(ctx, "GET", requestUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := wrapper.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != 200 {
+		return nil, newAPIError(requestUrl, response)
+	}
+	// spreadsheets which are not public are answered with a sign-in page
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if mediaType != "text/csv" {
+		response.Body.Close()
+		return nil, fmt.Errorf("%w: spreadsheet '%s' is not shared with anyone who has the link", ErrPermission, spreadSheetId)
+	}
+	return newCsvValueStream(response.Body), nil
+}
+
+func newCsvValueStream(body io.ReadCloser) *ValueStream {
+	csvReader := csv.NewReader(body)
+	csvReader.FieldsPerRecord = -1
+	return &ValueStream{
+		body:      body,
+		csvReader: csvReader,
+	}
+}
+
+// nextCsv returns the rows of a csv export like the rows of a values response:
+// trailing empty cells and rows are omitted and empty rows within the sheet are empty slices.
+func (stream *ValueStream) nextCsv() ([]any, error) {
+	if stream.emptyRows > 0 && stream.pending != nil {
+		stream.emptyRows--
+		return []any{}, nil
+	}
+	if stream.pending != nil {
+		row := stream.pending
+		stream.pending = nil
+		return row, nil
+	}
+	if stream.done {
+		return nil, io.EOF
+	}
+
+	for {
+		record, err := stream.csvReader.Read()
+		if err == io.EOF {
+			stream.done = true
+			return nil, io.EOF
+		}
+		if err != nil {
+			stream.done = true
+			return nil, fmt.Errorf("could not decode csv export: %w", err)
+		}
+		for len(record) > 0 && record[len(record)-1] == "" {
+			record = record[:len(record)-1]
+		}
+		if len(record) == 0 {
+			stream.emptyRows++
+			continue
+		}
+
+		row := make([]any, 0, len(record))
+		for _, value := range record {
+			row = append(row, value)
+		}
+		stream.pending = row
+		return stream.nextCsv()
+	}
+}
+
+// collectValues reads all rows of a stream
+func collectValues(stream *ValueStream) ([][]any, error) {
+	defer stream.Close()
+	result := make([][]any, 0)
+	for {
+		row, err := stream.Next()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+}
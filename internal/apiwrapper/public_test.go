@@ -0,0 +1,102 @@
+package apiwrapper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/internal/client"
+)
+
+func Test_ValueStream_Csv(t *testing.T) {
+	tests := map[string][][]any{
+		"a,b\n,\nc,\n":       {{"a", "b"}, {}, {"c"}},
+		"a,\"b,c\"\n":        {{"a", "b,c"}},
+		"a,\n,\n,\n":         {{"a"}},
+		",\n,\n,b\n":         {{}, {}, {"", "b"}},
+		"":                   {},
+		"a\n\"line\nbreak\"": {{"a"}, {"line\nbreak"}},
+	}
+	for body, expected := range tests {
+		stream := newCsvValueStream(io.NopCloser(strings.NewReader(body)))
+		actual, err := readStream(stream)
+		if err != nil {
+			t.Errorf("found error %v for %q", err, body)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected %v but found %v for %q", expected, actual, body)
+		}
+		if _, err = stream.Next(); err != io.EOF {
+			t.Errorf("expected EOF after the last row but found %v", err)
+		}
+	}
+}
+
+func Test_StreamRangeValues_Public(t *testing.T) {
+	mockClient := client.NewMockClient(func(request *http.Request) *http.Response {
+		expected := "https://docs.google.com/spreadsheets/d/spreadSheetId/gviz/tq?tqx=out:csv&headers=0&sheet=sheet+name"
+		if request.URL.String() != expected {
+			t.Errorf("expected url %s but found %s", expected, request.URL.String())
+		}
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": {"text/csv; charset=utf-8"}},
+			Body:       io.NopCloser(strings.NewReader("a,b\n,\n")),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient, WithPublicAccess(""))
+
+	actual, err := wrapper.GetRangeValues(context.Background(), "spreadSheetId", a1.Range{Sheet: "sheet name"})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if expected := [][]any{{"a", "b"}}; !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+}
+
+func Test_StreamRangeValues_Public_Private(t *testing.T) {
+	mockClient := client.NewMockClient(func(request *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+			Body:       io.NopCloser(strings.NewReader("<html></html>")),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient, WithPublicAccess(""))
+
+	_, err := wrapper.StreamRangeValues(context.Background(), "spreadSheetId", a1.Range{Sheet: "sheetName"})
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v but found %v", ErrPermission, err)
+	}
+}
+
+func Test_Public_ReadOnly(t *testing.T) {
+	mockClient := client.NewMockClient(func(request *http.Request) *http.Response {
+		t.Errorf("expected no request but found %s %s", request.Method, request.URL)
+		return &http.Response{StatusCode: 500, Body: io.NopCloser(strings.NewReader(""))}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient, WithPublicAccess(""))
+	partial, err := a1.ParseRange("sheetName!A1:B2")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	err = wrapper.ClearSheet(context.Background(), "spreadSheetId", "sheetName")
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v but found %v", ErrPermission, err)
+	}
+	_, err = wrapper.GetSpreadSheet(context.Background(), "spreadSheetId")
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v but found %v", ErrPermission, err)
+	}
+	_, err = wrapper.StreamRangeValues(context.Background(), "spreadSheetId", partial)
+	if !errors.Is(err, ErrPermission) {
+		t.Errorf("expected %v for a partial range but found %v", ErrPermission, err)
+	}
+}
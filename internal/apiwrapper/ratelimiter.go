@@ -0,0 +1,119 @@
+package apiwrapper
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Default quotas per user and project, see https://developers.google.com/sheets/api/limits
+const DefaultReadRequestsPerMinute = 60
+const DefaultWriteRequestsPerMinute = 60
+
+// RateLimiter blocks requests which would exceed the Sheets API quotas.
+// Read and write requests are limited independently.
+// A RateLimiter is safe for concurrent use and can be shared across multiple wrappers.
+type RateLimiter struct {
+	read  *tokenBucket
+	write *tokenBucket
+}
+
+// NewRateLimiter creates a limiter allowing the given number of read and write
+// requests per minute. A value lower than 1 disables the respective limit.
+//
+// Up to a tenth of the requests may be sent in a burst while the remaining
+// ones are spread evenly, so that no window of a minute exceeds the limit.
+func NewRateLimiter(readRequestsPerMinute int, writeRequestsPerMinute int) *RateLimiter {
+	return &RateLimiter{
+		read:  newTokenBucket(readRequestsPerMinute, time.Now, sleep),
+		write: newTokenBucket(writeRequestsPerMinute, time.Now, sleep),
+	}
+}
+
+// WithRateLimiter delays requests until the limiter allows them
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(wrapper *SheetsApiWrapper) {
+		wrapper.rateLimiter = limiter
+	}
+}
+
+// Wait blocks until a request with the given http method is allowed
+// or the context is done
+func (limiter *RateLimiter) Wait(ctx context.Context, method string) error {
+	if limiter == nil {
+		return nil
+	}
+	if method == "GET" {
+		return limiter.read.wait(ctx)
+	}
+	return limiter.write.wait(ctx)
+}
+
+type tokenBucket struct {
+	mutex     sync.Mutex
+	capacity  float64
+	tokens    float64
+	perSecond float64
+	last      time.Time
+	now       func() time.Time
+	sleep     func(ctx context.Context, duration time.Duration) error
+}
+
+func newTokenBucket(requestsPerMinute int, now func() time.Time, sleep func(ctx context.Context, duration time.Duration) error) *tokenBucket {
+	if requestsPerMinute < 1 {
+		return nil
+	}
+	burst := requestsPerMinute / 10
+	if burst < 1 {
+		burst = 1
+	}
+	perMinute := requestsPerMinute - burst
+	if perMinute < 1 {
+		perMinute = 1
+	}
+	return &tokenBucket{
+		capacity:  float64(burst),
+		tokens:    float64(burst),
+		perSecond: float64(perMinute) / 60,
+		last:      now(),
+		now:       now,
+		sleep:     sleep,
+	}
+}
+
+func (bucket *tokenBucket) wait(ctx context.Context) error {
+	if bucket == nil {
+		return ctx.Err()
+	}
+	for {
+		delay := bucket.take()
+		if delay == 0 {
+			return nil
+		}
+		if err := bucket.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// take removes a token from the bucket. If no token is available,
+// the time until the next token is returned.
+func (bucket *tokenBucket) take() time.Duration {
+	bucket.mutex.Lock()
+	defer bucket.mutex.Unlock()
+
+	now := bucket.now()
+	bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.perSecond
+	if bucket.tokens > bucket.capacity {
+		bucket.tokens = bucket.capacity
+	}
+	bucket.last = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0
+	}
+	// round up, otherwise a fraction of a nanosecond would be truncated to no delay at all
+	return time.Duration(math.Ceil((1 - bucket.tokens) / bucket.perSecond * float64(time.Second)))
+}
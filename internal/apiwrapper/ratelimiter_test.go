@@ -0,0 +1,130 @@
+package apiwrapper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jo-hoe/google-sheets/internal/client"
+)
+
+type fakeClock struct {
+	now   time.Time
+	slept time.Duration
+}
+
+func (clock *fakeClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *fakeClock) Sleep(ctx context.Context, duration time.Duration) error {
+	clock.now = clock.now.Add(duration)
+	clock.slept += duration
+	return nil
+}
+
+func Test_tokenBucket_Burst_Then_Throttle(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	bucket := newTokenBucket(60, clock.Now, clock.Sleep)
+
+	// the first 6 requests are a burst, afterwards 54 requests per minute are allowed
+	for i := 0; i < 6; i++ {
+		if err := bucket.wait(context.Background()); err != nil {
+			t.Fatalf("found error %v", err)
+		}
+	}
+	if clock.slept != 0 {
+		t.Errorf("expected burst without delay but slept %v", clock.slept)
+	}
+
+	if err := bucket.wait(context.Background()); err != nil {
+		t.Fatalf("found error %v", err)
+	}
+	expected := time.Minute / 54
+	if diff := clock.slept - expected; diff > time.Millisecond || diff < -time.Millisecond {
+		t.Errorf("expected delay of %v but found %v", expected, clock.slept)
+	}
+}
+
+func Test_tokenBucket_Never_Exceeds_Limit_Per_Minute(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	start := clock.now
+	bucket := newTokenBucket(100, clock.Now, clock.Sleep)
+
+	count := 0
+	for clock.now.Sub(start) < time.Minute {
+		if err := bucket.wait(context.Background()); err != nil {
+			t.Fatalf("found error %v", err)
+		}
+		if clock.now.Sub(start) < time.Minute {
+			count++
+		}
+	}
+
+	if count > 100 {
+		t.Errorf("expected at most 100 requests within a minute but found %d", count)
+	}
+}
+
+func Test_tokenBucket_Canceled_Context(t *testing.T) {
+	bucket := newTokenBucket(1, time.Now, sleep)
+	if err := bucket.wait(context.Background()); err != nil {
+		t.Fatalf("found error %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := bucket.wait(ctx)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected '%v' but found '%v'", context.Canceled, err)
+	}
+}
+
+func Test_RateLimiter_Separate_Buckets(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	limiter := &RateLimiter{
+		read:  newTokenBucket(1, clock.Now, clock.Sleep),
+		write: newTokenBucket(1, clock.Now, clock.Sleep),
+	}
+
+	for _, method := range []string{"GET", "POST"} {
+		if err := limiter.Wait(context.Background(), method); err != nil {
+			t.Fatalf("found error %v", err)
+		}
+	}
+
+	if clock.slept != 0 {
+		t.Errorf("expected read and write not to block each other but slept %v", clock.slept)
+	}
+}
+
+func Test_RateLimiter_Disabled(t *testing.T) {
+	limiter := NewRateLimiter(0, 0)
+
+	for i := 0; i < 1000; i++ {
+		if err := limiter.Wait(context.Background(), "GET"); err != nil {
+			t.Fatalf("found error %v", err)
+		}
+	}
+}
+
+func Test_WithRateLimiter(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	limiter := &RateLimiter{
+		read: newTokenBucket(1, clock.Now, clock.Sleep),
+	}
+	mockResponse := client.ResponseSummery{ResponseCode: 200, ResponseBody: `{"sheets": []}`}
+	wrapper := NewSheetsApiWrapper(client.CreateMockClient(mockResponse, mockResponse), WithRateLimiter(limiter))
+
+	for i := 0; i < 2; i++ {
+		if _, err := wrapper.GetSheetId(context.Background(), "spreadSheetId", "sheetName"); err != nil {
+			t.Fatalf("found error %v", err)
+		}
+	}
+
+	if clock.slept != time.Minute {
+		t.Errorf("expected second request to wait a minute but waited %v", clock.slept)
+	}
+}
@@ -0,0 +1,81 @@
+package apiwrapper
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ValueRenderOption determines how values are returned when reading,
+// see https://developers.google.com/sheets/api/reference/rest/v4/ValueRenderOption
+type ValueRenderOption string
+
+const (
+	// RenderFormatted returns values as displayed in the UI, e.g. "1,234.50 €" (API default)
+	RenderFormatted ValueRenderOption = "FORMATTED_VALUE"
+	// RenderUnformatted returns numbers and booleans without formatting, e.g. 1234.5
+	RenderUnformatted ValueRenderOption = "UNFORMATTED_VALUE"
+	// RenderFormula returns the formulas of computed cells instead of their results
+	RenderFormula ValueRenderOption = "FORMULA"
+)
+
+// DateTimeRenderOption determines how dates and times are returned when reading.
+// It is ignored if the values are read with RenderFormatted.
+type DateTimeRenderOption string
+
+const (
+	// DateTimeSerialNumber returns dates as number of days since December 30th 1899 (API default)
+	DateTimeSerialNumber DateTimeRenderOption = "SERIAL_NUMBER"
+	// DateTimeFormattedString returns dates as formatted by the number format of the cell
+	DateTimeFormattedString DateTimeRenderOption = "FORMATTED_STRING"
+)
+
+// WithValueRenderOption sets how values are returned by reads (default RenderFormatted)
+func WithValueRenderOption(option ValueRenderOption) Option {
+	return func(wrapper *SheetsApiWrapper) {
+		wrapper.renderOption = option
+	}
+}
+
+// WithDateTimeRenderOption sets how dates and times are returned by reads (default DateTimeSerialNumber)
+func WithDateTimeRenderOption(option DateTimeRenderOption) Option {
+	return func(wrapper *SheetsApiWrapper) {
+		wrapper.dateTimeOption = option
+	}
+}
+
+// renderQuery returns the query parameters for the configured render options
+// prefixed with "&", or an empty string to use the API defaults
+func (wrapper SheetsApiWrapper) renderQuery() string {
+	query := url.Values{}
+	if wrapper.renderOption != "" {
+		query.Set("valueRenderOption", string(wrapper.renderOption))
+	}
+	if wrapper.dateTimeOption != "" {
+		query.Set("dateTimeRenderOption", string(wrapper.dateTimeOption))
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "&" + query.Encode()
+}
+
+// FormatValue converts a typed value of the API into its csv representation,
+// e.g. 1234.5 into "1234.5" and true into "TRUE"
+func FormatValue(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case bool:
+		if typed {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return fmt.Sprint(typed)
+	}
+}
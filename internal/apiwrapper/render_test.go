@@ -0,0 +1,99 @@
+package apiwrapper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/internal/client"
+)
+
+const typedValuesResponse = `{"range":"Sheet1!A1:C2","majorDimension":"ROWS","values":[["name",1234.5,true],["formula","=A1",false]]}`
+
+func createRenderWrapper(request **http.Request, opts ...Option) *SheetsApiWrapper {
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		*request = req
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(typedValuesResponse)),
+			Header:     make(http.Header),
+		}
+	})
+	return NewSheetsApiWrapper(mockClient, opts...)
+}
+
+func Test_GetRangeValues(t *testing.T) {
+	request := &http.Request{}
+	wrapper := createRenderWrapper(&request, WithValueRenderOption(RenderUnformatted), WithDateTimeRenderOption(DateTimeFormattedString))
+
+	actual, err := wrapper.GetRangeValues(context.Background(), "spreadSheetId", a1.Range{Sheet: "Sheet1"})
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+
+	expected := [][]any{{"name", 1234.5, true}, {"formula", "=A1", false}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+	query := request.URL.Query()
+	if query.Get("valueRenderOption") != "UNFORMATTED_VALUE" || query.Get("dateTimeRenderOption") != "FORMATTED_STRING" {
+		t.Errorf("expected render options in query but found '%s'", request.URL.RawQuery)
+	}
+}
+
+func Test_GetRangeRecords_Typed_Values(t *testing.T) {
+	request := &http.Request{}
+	wrapper := createRenderWrapper(&request)
+
+	actual, err := wrapper.GetRangeRecords(context.Background(), "spreadSheetId", a1.Range{Sheet: "Sheet1"})
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+
+	expected := [][]string{{"name", "1234.5", "TRUE"}, {"formula", "=A1", "FALSE"}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+	if request.URL.Query().Has("valueRenderOption") {
+		t.Errorf("expected API default render option but found '%s'", request.URL.RawQuery)
+	}
+}
+
+func Test_formatValue(t *testing.T) {
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{nil, ""},
+		{"text", "text"},
+		{1234.5, "1234.5"},
+		{1e21, "1000000000000000000000"},
+		{float64(3), "3"},
+		{true, "TRUE"},
+		{false, "FALSE"},
+	}
+	for _, test := range tests {
+		if actual := FormatValue(test.value); actual != test.expected {
+			t.Errorf("expected '%s' for %v but found '%s'", test.expected, test.value, actual)
+		}
+	}
+}
+
+func Test_GetRangeRecords(t *testing.T) {
+	request := &http.Request{}
+	wrapper := createRenderWrapper(&request, WithValueRenderOption(RenderUnformatted))
+
+	actual, err := wrapper.GetRangeRecords(context.Background(), "spreadSheetId", a1.Range{Sheet: "Sheet1"})
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+
+	expected := [][]string{{"name", "1234.5", "TRUE"}, {"formula", "=A1", "FALSE"}}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v but found %v", expected, actual)
+	}
+}
@@ -0,0 +1,125 @@
+package apiwrapper
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how requests failing with a quota (429) or a server (5xx)
+// error are retried. The zero value disables retries.
+//
+// Requests which are not idempotent (e.g. appending rows) are only retried if
+// the API rejected them without processing, i.e. with status 429.
+// Otherwise a retry could duplicate data.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	// A Retry-After header of the response takes precedence.
+	MaxBackoff time.Duration
+	// Multiplier is the factor by which the delay grows after each attempt.
+	Multiplier float64
+	// Jitter randomizes each delay by up to the given fraction (0 to 1),
+	// so that parallel clients do not retry in lockstep.
+	Jitter float64
+}
+
+// DefaultRetryPolicy returns a policy implementing the exponential backoff
+// recommended in https://developers.google.com/sheets/api/limits
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 1 * time.Second,
+		MaxBackoff:     32 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+// WithRetryPolicy retries failed requests according to the given policy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(wrapper *SheetsApiWrapper) {
+		wrapper.retryPolicy = policy
+	}
+}
+
+// shouldRetry decides if a request is retried after the given attempt failed.
+// Either err is set for transport errors or statusCode contains the response status.
+func (policy RetryPolicy) shouldRetry(ctx context.Context, attempt int, statusCode int, err error, idempotent bool) bool {
+	if attempt >= policy.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		// the request may have reached the server before the connection failed
+		return idempotent && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before the next attempt. random is expected to be in [0,1).
+func (policy RetryPolicy) backoff(attempt int, retryAfter time.Duration, random float64) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	delay = delay * (1 + policy.Jitter*(2*random-1))
+	if policy.MaxBackoff > 0 && delay > float64(policy.MaxBackoff) {
+		delay = float64(policy.MaxBackoff)
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return time.Duration(delay)
+}
+
+// parseRetryAfter reads the Retry-After header which either contains
+// the delay in seconds or a http date
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// sleep waits for the given duration or until the context is done
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func random() float64 {
+	return rand.Float64()
+}
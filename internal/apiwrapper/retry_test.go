@@ -0,0 +1,215 @@
+package apiwrapper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jo-hoe/google-sheets/internal/client"
+)
+
+func Test_Retry_TooManyRequests_Not_Idempotent(t *testing.T) {
+	mockClient := client.CreateMockClient(
+		client.ResponseSummery{ResponseCode: 429},
+		client.ResponseSummery{ResponseCode: 200},
+	)
+	wrapper, delays := createRetryWrapper(mockClient)
+
+	err := wrapper.AppendToSheet(context.Background(), "spreadSheetId", "sheetName", [][]string{{"0"}})
+
+	if err != nil {
+		t.Errorf("found error %v", err)
+	}
+	if len(*delays) != 1 {
+		t.Errorf("expected 1 retry but found %d", len(*delays))
+	}
+}
+
+func Test_Retry_ServerError_Not_Idempotent(t *testing.T) {
+	mockClient := client.CreateMockClient(
+		client.ResponseSummery{ResponseCode: 503},
+		client.ResponseSummery{ResponseCode: 200},
+	)
+	wrapper, delays := createRetryWrapper(mockClient)
+
+	err := wrapper.AppendToSheet(context.Background(), "spreadSheetId", "sheetName", [][]string{{"0"}})
+
+	if err == nil {
+		t.Error("expected error since append must not be retried")
+	}
+	if len(*delays) != 0 {
+		t.Errorf("expected no retry but found %d", len(*delays))
+	}
+}
+
+func Test_Retry_ServerError_Idempotent(t *testing.T) {
+	mockClient := client.CreateMockClient(
+		client.ResponseSummery{ResponseCode: 500},
+		client.ResponseSummery{ResponseCode: 503},
+		client.ResponseSummery{ResponseCode: 200, ResponseBody: `{"sheets": [{"properties": {"sheetId": 1, "title": "sheetName"}}]}`},
+	)
+	wrapper, delays := createRetryWrapper(mockClient)
+
+	id, err := wrapper.GetSheetId(context.Background(), "spreadSheetId", "sheetName")
+
+	if err != nil {
+		t.Errorf("found error %v", err)
+	}
+	if id != 1 {
+		t.Errorf("expected id 1 but found %d", id)
+	}
+	expected := []time.Duration{time.Second, 2 * time.Second}
+	if !reflect.DeepEqual(expected, *delays) {
+		t.Errorf("expected delays %v but found %v", expected, *delays)
+	}
+}
+
+func Test_Retry_Exhausted(t *testing.T) {
+	mockClient := client.CreateMockClient(
+		client.ResponseSummery{ResponseCode: 429},
+		client.ResponseSummery{ResponseCode: 429},
+		client.ResponseSummery{ResponseCode: 429},
+	)
+	wrapper, delays := createRetryWrapper(mockClient)
+
+	err := wrapper.ClearSheet(context.Background(), "spreadSheetId", "sheetName")
+
+	if err == nil {
+		t.Error("expected error after all attempts failed")
+	}
+	if len(*delays) != 2 {
+		t.Errorf("expected 2 retries but found %d", len(*delays))
+	}
+}
+
+func Test_Retry_Disabled_By_Default(t *testing.T) {
+	mockClient := client.CreateMockClient(
+		client.ResponseSummery{ResponseCode: 429},
+		client.ResponseSummery{ResponseCode: 200},
+	)
+	wrapper := NewSheetsApiWrapper(mockClient)
+
+	err := wrapper.ClearSheet(context.Background(), "spreadSheetId", "sheetName")
+
+	if err == nil {
+		t.Error("expected error since retries are disabled")
+	}
+}
+
+func Test_Retry_After(t *testing.T) {
+	i := 0
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		i++
+		response := &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("")),
+			Header:     make(http.Header),
+		}
+		if i == 1 {
+			response.StatusCode = 429
+			response.Header.Set("Retry-After", "7")
+		}
+		return response
+	})
+	wrapper, delays := createRetryWrapper(mockClient)
+
+	err := wrapper.ClearSheet(context.Background(), "spreadSheetId", "sheetName")
+
+	if err != nil {
+		t.Errorf("found error %v", err)
+	}
+	expected := []time.Duration{7 * time.Second}
+	if !reflect.DeepEqual(expected, *delays) {
+		t.Errorf("expected delays %v but found %v", expected, *delays)
+	}
+}
+
+func Test_Retry_Resends_Body(t *testing.T) {
+	bodies := make([]string, 0)
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		statusCode := 200
+		if len(bodies) == 1 {
+			statusCode = 429
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(strings.NewReader("")),
+			Header:     make(http.Header),
+		}
+	})
+	wrapper, _ := createRetryWrapper(mockClient)
+
+	err := wrapper.AppendToSheet(context.Background(), "spreadSheetId", "sheetName", [][]string{{"0"}})
+
+	if err != nil {
+		t.Errorf("found error %v", err)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[0] == "" {
+		t.Errorf("expected the same body to be sent twice but found %v", bodies)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+
+	tests := []struct {
+		attempt    int
+		retryAfter time.Duration
+		random     float64
+		want       time.Duration
+	}{
+		{attempt: 1, random: 0.5, want: time.Second},
+		{attempt: 3, random: 0.5, want: 4 * time.Second},
+		{attempt: 3, random: 0, want: 2 * time.Second},
+		{attempt: 10, random: 0.5, want: 10 * time.Second},
+		{attempt: 1, retryAfter: 30 * time.Second, random: 0.5, want: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.attempt, tt.retryAfter, tt.random); got != tt.want {
+			t.Errorf("backoff(%d, %v, %v) = %v, want %v", tt.attempt, tt.retryAfter, tt.random, got, tt.want)
+		}
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"3":                             3 * time.Second,
+		"Mon, 01 Jan 2024 00:00:10 GMT": 10 * time.Second,
+		"invalid":                       0,
+	}
+	for value, expected := range tests {
+		header := make(http.Header)
+		header.Set("Retry-After", value)
+		if actual := parseRetryAfter(header, now); actual != expected {
+			t.Errorf("expected %v for '%s' but found %v", expected, value, actual)
+		}
+	}
+}
+
+func createRetryWrapper(httpClient *http.Client) (*SheetsApiWrapper, *[]time.Duration) {
+	delays := make([]time.Duration, 0)
+	wrapper := NewSheetsApiWrapper(httpClient, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Multiplier:     2,
+	}))
+	wrapper.sleep = func(ctx context.Context, duration time.Duration) error {
+		delays = append(delays, duration)
+		return nil
+	}
+	return wrapper, &delays
+}
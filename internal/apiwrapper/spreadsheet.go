@@ -0,0 +1,326 @@
+package apiwrapper
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+)
+
+// SpreadSheetMetadata describes a spreadsheet and its sheets
+type SpreadSheetMetadata struct {
+	Id       string
+	Url      string
+	Title    string
+	Locale   string
+	TimeZone string
+	Sheets   []SheetProperties
+}
+
+// SheetProperties describes a sheet of a spreadsheet
+type SheetProperties struct {
+	Id    int32
+	Title string
+	// position of the sheet within the spreadsheet, starting with 0
+	Index       int
+	RowCount    int
+	ColumnCount int
+	// number of rows and columns which stay visible when scrolling
+	FrozenRowCount    int
+	FrozenColumnCount int
+	Hidden            bool
+	// color of the tab, nil if the tab has no color
+	TabColor color.Color
+}
+
+// CreateSpreadSheet creates a new spreadsheet with the given title.
+// Google Sheets adds a first sheet named "Sheet1".
+func (wrapper SheetsApiWrapper) CreateSpreadSheet(ctx context.Context, title string) (SpreadSheetMetadata, error) {
+	body := spreadSheet{
+		Properties: documentProperties{
+			Title: title,
+		},
+	}
+
+	// not idempotent, a repeated request would create another spreadsheet
+	response, err := wrapper.postSheetRequest(ctx, wrapper.url(spreadSheetsUrl), body, false)
+	if err != nil {
+		return SpreadSheetMetadata{}, err
+	}
+
+	result := spreadSheet{}
+	err = deserialize[spreadSheet](response, &result)
+	if err != nil {
+		return SpreadSheetMetadata{}, err
+	}
+	return result.toMetadata(), nil
+}
+
+// GetSheetProperties returns the properties of a sheet.
+// If the sheet does not exist, an error matching ErrNotExist is returned.
+func (wrapper SheetsApiWrapper) GetSheetProperties(ctx context.Context, spreadSheetId string, sheetName string) (SheetProperties, error) {
+	metadata, err := wrapper.GetSpreadSheet(ctx, spreadSheetId)
+	if err != nil {
+		return SheetProperties{}, err
+	}
+	for _, properties := range metadata.Sheets {
+		if properties.Title == sheetName {
+			return properties, nil
+		}
+	}
+	return SheetProperties{}, fmt.Errorf("%w: sheet '%s'", ErrNotExist, sheetName)
+}
+
+// DuplicateSheet copies a sheet within its spreadsheet. The copy is named
+// newName and inserted at the zero based index, the following sheets move back.
+func (wrapper SheetsApiWrapper) DuplicateSheet(ctx context.Context, spreadSheetId string, sheetId int32, newName string, index int) (SheetProperties, error) {
+	body := updateRequest{}
+	body.Request = []batchRequest{{
+		DuplicateSheet: &duplicateSheet{
+			SourceSheetId:    sheetId,
+			InsertSheetIndex: index,
+			NewSheetName:     newName,
+		}}}
+
+	// not idempotent, a repeated request would fail since the name is taken
+	response, err := wrapper.postSheetRequest(ctx, wrapper.url(updateSheetUrl, spreadSheetId), body, false)
+	if err != nil {
+		return SheetProperties{}, err
+	}
+
+	result := batchResponse{}
+	err = deserialize[batchResponse](response, &result)
+	if err != nil {
+		return SheetProperties{}, err
+	}
+	if len(result.Replies) == 0 || result.Replies[0].DuplicateSheet == nil {
+		return SheetProperties{}, fmt.Errorf("response does not contain the duplicated sheet")
+	}
+	return result.Replies[0].DuplicateSheet.Properties.toSheetProperties(), nil
+}
+
+// CopySheetTo copies a sheet into another spreadsheet, where it is added as
+// last sheet. The copy is named by the API, e.g. "Copy of Sheet1".
+func (wrapper SheetsApiWrapper) CopySheetTo(ctx context.Context, spreadSheetId string, sheetId int32, destinationSpreadSheetId string) (SheetProperties, error) {
+	body := copySheetRequest{
+		DestinationSpreadSheetId: destinationSpreadSheetId,
+	}
+
+	// not idempotent, a repeated request would create another copy
+	response, err := wrapper.postSheetRequest(ctx, wrapper.url(copySheetUrl, spreadSheetId, sheetId), body, false)
+	if err != nil {
+		return SheetProperties{}, err
+	}
+
+	result := spreadSheetProperties{}
+	err = deserialize[spreadSheetProperties](response, &result)
+	if err != nil {
+		return SheetProperties{}, err
+	}
+	return result.toSheetProperties(), nil
+}
+
+// RenameSheet changes the title of a sheet
+func (wrapper SheetsApiWrapper) RenameSheet(ctx context.Context, spreadSheetId string, sheetId int32, title string) error {
+	return wrapper.updateSheetProperties(ctx, spreadSheetId, spreadSheetProperties{SheetID: sheetId, Title: title}, "title")
+}
+
+// MoveSheet changes the position of a sheet. As defined by the API the index
+// refers to the positions before the move, e.g. to move the first of three
+// sheets behind the second one the index is 2.
+func (wrapper SheetsApiWrapper) MoveSheet(ctx context.Context, spreadSheetId string, sheetId int32, index int) error {
+	return wrapper.updateSheetProperties(ctx, spreadSheetId, spreadSheetProperties{SheetID: sheetId, Index: index}, "index")
+}
+
+// SetSheetHidden hides or shows a sheet in the UI
+func (wrapper SheetsApiWrapper) SetSheetHidden(ctx context.Context, spreadSheetId string, sheetId int32, hidden bool) error {
+	return wrapper.updateSheetProperties(ctx, spreadSheetId, spreadSheetProperties{SheetID: sheetId, Hidden: hidden}, "hidden")
+}
+
+// SetTabColor sets the color of the tab of a sheet, nil removes the color
+func (wrapper SheetsApiWrapper) SetTabColor(ctx context.Context, spreadSheetId string, sheetId int32, tabColor color.Color) error {
+	properties := spreadSheetProperties{SheetID: sheetId}
+	if tabColor != nil {
+		properties.TabColorStyle = &colorStyle{RgbColor: toRgbColor(tabColor)}
+	}
+	return wrapper.updateSheetProperties(ctx, spreadSheetId, properties, "tabColorStyle")
+}
+
+// SetFrozen sets the number of rows and columns which stay visible when scrolling
+func (wrapper SheetsApiWrapper) SetFrozen(ctx context.Context, spreadSheetId string, sheetId int32, rows int, columns int) error {
+	properties := spreadSheetProperties{
+		SheetID: sheetId,
+		GridProperties: gridProperties{
+			FrozenRowCount:    rows,
+			FrozenColumnCount: columns,
+		},
+	}
+	return wrapper.updateSheetProperties(ctx, spreadSheetId, properties, "gridProperties.frozenRowCount,gridProperties.frozenColumnCount")
+}
+
+func (wrapper SheetsApiWrapper) updateSheetProperties(ctx context.Context, spreadSheetId string, properties spreadSheetProperties, fields string) error {
+	body := updateRequest{}
+	body.Request = []batchRequest{{
+		UpdateSheetProperties: &updateSheetProperties{
+			Properties: properties,
+			Fields:     fields,
+		}}}
+
+	response, err := wrapper.postSheetRequest(ctx, wrapper.url(updateSheetUrl, spreadSheetId), body, true)
+	if response != nil {
+		response.Close()
+	}
+	return err
+}
+
+// GetDataRange returns the smallest range containing all non empty cells of a sheet.
+// The values are streamed, so only a single row is kept in memory.
+// For an empty sheet the returned range has no cells.
+func (wrapper SheetsApiWrapper) GetDataRange(ctx context.Context, spreadSheetId string, sheetName string) (a1.Range, error) {
+	stream, err := wrapper.StreamRangeValues(ctx, spreadSheetId, a1.Range{Sheet: sheetName})
+	if err != nil {
+		return a1.Range{}, err
+	}
+	defer stream.Close()
+
+	result := a1.Range{Sheet: sheetName}
+	for row := 1; ; row++ {
+		values, err := stream.Next()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return a1.Range{}, err
+		}
+		for i, value := range values {
+			if FormatValue(value) == "" {
+				continue
+			}
+			column := i + 1
+			if result.Start.Row == 0 {
+				result.Start.Row = row
+			}
+			if result.Start.Column == 0 || column < result.Start.Column {
+				result.Start.Column = column
+			}
+			if column > result.End.Column {
+				result.End.Column = column
+			}
+			result.End.Row = row
+		}
+	}
+}
+
+// FirstValue returns the first non empty cell of a sheet in row order, ok is false for an
+// empty sheet. In contrast to GetDataRange the response is only read up to that cell.
+func (wrapper SheetsApiWrapper) FirstValue(ctx context.Context, spreadSheetId string, sheetName string) (cell a1.Cell, ok bool, err error) {
+	stream, err := wrapper.StreamRangeValues(ctx, spreadSheetId, a1.Range{Sheet: sheetName})
+	if err != nil {
+		return a1.Cell{}, false, err
+	}
+	defer stream.Close()
+
+	for row := 1; ; row++ {
+		values, err := stream.Next()
+		if err == io.EOF {
+			return a1.Cell{}, false, nil
+		}
+		if err != nil {
+			return a1.Cell{}, false, err
+		}
+		for i, value := range values {
+			if FormatValue(value) != "" {
+				return a1.Cell{Column: i + 1, Row: row}, true, nil
+			}
+		}
+	}
+}
+
+// GetSpreadSheet returns the properties of a spreadsheet and its sheets
+func (wrapper SheetsApiWrapper) GetSpreadSheet(ctx context.Context, spreadSheetId string) (SpreadSheetMetadata, error) {
+	response, err := wrapper.getSheetRequest(ctx, wrapper.url(baseUrl, spreadSheetId))
+	if err != nil {
+		return SpreadSheetMetadata{}, err
+	}
+
+	result := spreadSheet{}
+	err = deserialize[spreadSheet](response, &result)
+	if err != nil {
+		return SpreadSheetMetadata{}, err
+	}
+	if result.SpreadSheetId == "" {
+		result.SpreadSheetId = spreadSheetId
+	}
+	return result.toMetadata(), nil
+}
+
+func (document spreadSheet) toMetadata() SpreadSheetMetadata {
+	result := SpreadSheetMetadata{
+		Id:       document.SpreadSheetId,
+		Url:      document.SpreadSheetUrl,
+		Title:    document.Properties.Title,
+		Locale:   document.Properties.Locale,
+		TimeZone: document.Properties.TimeZone,
+		Sheets:   make([]SheetProperties, 0, len(document.Sheets)),
+	}
+	for _, sheet := range document.Sheets {
+		result.Sheets = append(result.Sheets, sheet.Properties.toSheetProperties())
+	}
+	return result
+}
+
+func (properties spreadSheetProperties) toSheetProperties() SheetProperties {
+	return SheetProperties{
+		Id:                properties.SheetID,
+		Title:             properties.Title,
+		Index:             properties.Index,
+		RowCount:          properties.GridProperties.RowCount,
+		ColumnCount:       properties.GridProperties.ColumnCount,
+		FrozenRowCount:    properties.GridProperties.FrozenRowCount,
+		FrozenColumnCount: properties.GridProperties.FrozenColumnCount,
+		Hidden:            properties.Hidden,
+		TabColor:          properties.tabColor(),
+	}
+}
+
+// tabColor prefers the rgb color of the style, a theme color can only be
+// resolved through the deprecated tabColor field
+func (properties spreadSheetProperties) tabColor() color.Color {
+	if properties.TabColorStyle != nil && properties.TabColorStyle.RgbColor != nil {
+		return properties.TabColorStyle.RgbColor.toColor()
+	}
+	if properties.TabColor != nil {
+		return properties.TabColor.toColor()
+	}
+	return nil
+}
+
+func (rgb rgbColor) toColor() color.Color {
+	alpha := 1.0
+	if rgb.Alpha != nil {
+		alpha = *rgb.Alpha
+	}
+	return color.NRGBA{
+		R: toComponent(rgb.Red),
+		G: toComponent(rgb.Green),
+		B: toComponent(rgb.Blue),
+		A: toComponent(alpha),
+	}
+}
+
+// toRgbColor converts a color, its transparency is ignored since tabs are opaque
+func toRgbColor(value color.Color) *rgbColor {
+	nrgba := color.NRGBAModel.Convert(value).(color.NRGBA)
+	return &rgbColor{
+		Red:   float64(nrgba.R) / 255,
+		Green: float64(nrgba.G) / 255,
+		Blue:  float64(nrgba.B) / 255,
+	}
+}
+
+func toComponent(value float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, value)) * 255))
+}
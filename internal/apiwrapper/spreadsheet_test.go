@@ -0,0 +1,257 @@
+package apiwrapper
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+	"github.com/jo-hoe/google-sheets/internal/client"
+)
+
+const spreadSheetResponse = `{
+	"spreadsheetId": "spreadSheetId",
+	"spreadsheetUrl": "https://docs.google.com/spreadsheets/d/spreadSheetId/edit",
+	"properties": {"title": "Budget", "locale": "de_DE", "autoRecalc": "ON_CHANGE", "timeZone": "Europe/Berlin"},
+	"sheets": [
+		{"properties": {"sheetId": 0, "title": "Sheet1", "index": 0, "sheetType": "GRID", "gridProperties": {"rowCount": 1000, "columnCount": 26}}},
+		{"properties": {"sheetId": 7, "title": "Archive", "index": 1, "sheetType": "GRID", "hidden": true, "gridProperties": {"rowCount": 10, "columnCount": 2, "frozenRowCount": 1},
+			"tabColor": {"red": 1}, "tabColorStyle": {"rgbColor": {"red": 1, "blue": 0.5}}}},
+		{"properties": {"sheetId": 8, "title": "Themed", "index": 2, "sheetType": "GRID", "gridProperties": {"rowCount": 10, "columnCount": 2, "frozenColumnCount": 2},
+			"tabColor": {"green": 1, "alpha": 0.5}, "tabColorStyle": {"themeColor": "ACCENT1"}}}
+	]
+}`
+
+func Test_GetSpreadSheet(t *testing.T) {
+	mockResponse := client.ResponseSummery{
+		ResponseCode: 200,
+		ResponseBody: spreadSheetResponse,
+	}
+	wrapper := NewSheetsApiWrapper(client.CreateMockClient(mockResponse))
+
+	actual, err := wrapper.GetSpreadSheet(context.Background(), "spreadSheetId")
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+	expected := SpreadSheetMetadata{
+		Id:       "spreadSheetId",
+		Url:      "https://docs.google.com/spreadsheets/d/spreadSheetId/edit",
+		Title:    "Budget",
+		Locale:   "de_DE",
+		TimeZone: "Europe/Berlin",
+		Sheets: []SheetProperties{
+			{Id: 0, Title: "Sheet1", Index: 0, RowCount: 1000, ColumnCount: 26},
+			{Id: 7, Title: "Archive", Index: 1, RowCount: 10, ColumnCount: 2, FrozenRowCount: 1, Hidden: true, TabColor: color.NRGBA{R: 255, B: 128, A: 255}},
+			{Id: 8, Title: "Themed", Index: 2, RowCount: 10, ColumnCount: 2, FrozenColumnCount: 2, TabColor: color.NRGBA{G: 255, A: 128}},
+		},
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v but found %+v", expected, actual)
+	}
+}
+
+func Test_CreateSpreadSheet(t *testing.T) {
+	request := &http.Request{}
+	body := ""
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		request = req
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(spreadSheetResponse)),
+			Header:     make(http.Header),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
+
+	actual, err := wrapper.CreateSpreadSheet(context.Background(), "Budget")
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+	if actual.Id != "spreadSheetId" || len(actual.Sheets) != 3 {
+		t.Errorf("unexpected spreadsheet %+v", actual)
+	}
+	if request.Method != http.MethodPost || request.URL.String() != DefaultEndpoint+"v4/spreadsheets" {
+		t.Errorf("unexpected request %s %s", request.Method, request.URL)
+	}
+	expectedBody := `{"properties":{"title":"Budget"}}`
+	if body != expectedBody {
+		t.Errorf("expected body %s but found %s", expectedBody, body)
+	}
+}
+
+func Test_GetSheetProperties(t *testing.T) {
+	mockResponse := client.ResponseSummery{
+		ResponseCode: 200,
+		ResponseBody: spreadSheetResponse,
+	}
+	wrapper := NewSheetsApiWrapper(client.CreateMockClient(mockResponse, mockResponse))
+
+	actual, err := wrapper.GetSheetProperties(context.Background(), "spreadSheetId", "Archive")
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+	if actual.Id != 7 || actual.FrozenRowCount != 1 {
+		t.Errorf("unexpected properties %+v", actual)
+	}
+
+	_, err = wrapper.GetSheetProperties(context.Background(), "spreadSheetId", "Unknown")
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected ErrNotExist but found %v", err)
+	}
+}
+
+func Test_GetDataRange(t *testing.T) {
+	tests := map[string]string{
+		`{"range":"Data!A1:Z1000","majorDimension":"ROWS","values":[["a","b"],["c"]]}`:            "Data!A1:B2",
+		`{"range":"Data!A1:Z1000","majorDimension":"ROWS","values":[[],["","",1],["","x"],[""]]}`: "Data!B2:C3",
+		`{"range":"Data!A1:Z1000","majorDimension":"ROWS","values":[[],[""]]}`:                    "Data",
+		`{"range":"Data!A1:Z1000","majorDimension":"ROWS"}`:                                       "Data",
+	}
+	for body, expected := range tests {
+		mockResponse := client.ResponseSummery{
+			ResponseCode: 200,
+			ResponseBody: body,
+		}
+		wrapper := NewSheetsApiWrapper(client.CreateMockClient(mockResponse))
+
+		actual, err := wrapper.GetDataRange(context.Background(), "spreadSheetId", "Data")
+		if err != nil {
+			t.Fatalf("found error %v", err)
+		}
+		expectedRange, err := a1.ParseRange(expected)
+		if err != nil {
+			t.Fatalf("found error %v", err)
+		}
+		if actual != expectedRange {
+			t.Errorf("expected %v but found %v for %s", expected, actual, body)
+		}
+	}
+}
+
+func Test_FirstValue(t *testing.T) {
+	tests := map[string]a1.Cell{
+		`{"range":"Data!A1:Z1000","majorDimension":"ROWS","values":[["a","b"],["c"]]}`: {Column: 1, Row: 1},
+		// the response is not read beyond the first value
+		`{"range":"Data!A1:Z1000","majorDimension":"ROWS","values":[[],["","",1],invalid`: {Column: 3, Row: 2},
+		`{"range":"Data!A1:Z1000","majorDimension":"ROWS","values":[[],[""]]}`:            {},
+		`{"range":"Data!A1:Z1000","majorDimension":"ROWS"}`:                               {},
+	}
+	for body, expected := range tests {
+		mockResponse := client.ResponseSummery{
+			ResponseCode: 200,
+			ResponseBody: body,
+		}
+		wrapper := NewSheetsApiWrapper(client.CreateMockClient(mockResponse))
+
+		actual, ok, err := wrapper.FirstValue(context.Background(), "spreadSheetId", "Data")
+		if err != nil {
+			t.Fatalf("found error %v", err)
+		}
+		if actual != expected || ok != (expected != a1.Cell{}) {
+			t.Errorf("expected %v but found %v (%t) for %s", expected, actual, ok, body)
+		}
+	}
+}
+
+func Test_updateSheetProperties(t *testing.T) {
+	tests := map[string]func(wrapper *SheetsApiWrapper) error{
+		`{"requests":[{"updateSheetProperties":{"properties":{"sheetId":5,"gridProperties":{"frozenRowCount":1}},"fields":"gridProperties.frozenRowCount,gridProperties.frozenColumnCount"}}],"includeSpreadsheetInResponse":false,"responseIncludeGridData":false}`: func(wrapper *SheetsApiWrapper) error {
+			return wrapper.SetFrozen(context.Background(), "spreadSheetId", 5, 1, 0)
+		},
+		`{"requests":[{"updateSheetProperties":{"properties":{"sheetId":5,"gridProperties":{},"tabColorStyle":{"rgbColor":{"red":1,"blue":0.2}}},"fields":"tabColorStyle"}}],"includeSpreadsheetInResponse":false,"responseIncludeGridData":false}`: func(wrapper *SheetsApiWrapper) error {
+			return wrapper.SetTabColor(context.Background(), "spreadSheetId", 5, color.RGBA{R: 255, B: 51, A: 255})
+		},
+		`{"requests":[{"updateSheetProperties":{"properties":{"sheetId":5,"gridProperties":{}},"fields":"tabColorStyle"}}],"includeSpreadsheetInResponse":false,"responseIncludeGridData":false}`: func(wrapper *SheetsApiWrapper) error {
+			return wrapper.SetTabColor(context.Background(), "spreadSheetId", 5, nil)
+		},
+		`{"requests":[{"updateSheetProperties":{"properties":{"sheetId":5,"title":"Report","gridProperties":{}},"fields":"title"}}],"includeSpreadsheetInResponse":false,"responseIncludeGridData":false}`: func(wrapper *SheetsApiWrapper) error {
+			return wrapper.RenameSheet(context.Background(), "spreadSheetId", 5, "Report")
+		},
+	}
+	for expected, update := range tests {
+		body := ""
+		mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+			data, _ := io.ReadAll(req.Body)
+			body = string(data)
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader("{}")),
+				Header:     make(http.Header),
+			}
+		})
+
+		err := update(NewSheetsApiWrapper(mockClient))
+		if err != nil {
+			t.Errorf("found error %v", err)
+		}
+		if body != expected {
+			t.Errorf("expected body %s but found %s", expected, body)
+		}
+	}
+}
+
+func Test_DuplicateSheet(t *testing.T) {
+	body := ""
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"spreadsheetId":"spreadSheetId","replies":[{"duplicateSheet":{"properties":{"sheetId":9,"title":"Customer","index":2,"gridProperties":{"rowCount":10,"columnCount":3}}}}]}`)),
+			Header:     make(http.Header),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
+
+	actual, err := wrapper.DuplicateSheet(context.Background(), "spreadSheetId", 5, "Customer", 2)
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+	expected := SheetProperties{Id: 9, Title: "Customer", Index: 2, RowCount: 10, ColumnCount: 3}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v but found %+v", expected, actual)
+	}
+	expectedBody := `{"requests":[{"duplicateSheet":{"sourceSheetId":5,"insertSheetIndex":2,"newSheetName":"Customer"}}],"includeSpreadsheetInResponse":false,"responseIncludeGridData":false}`
+	if body != expectedBody {
+		t.Errorf("expected body %s but found %s", expectedBody, body)
+	}
+}
+
+func Test_CopySheetTo(t *testing.T) {
+	request := &http.Request{}
+	body := ""
+	mockClient := client.NewMockClient(func(req *http.Request) *http.Response {
+		request = req
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"sheetId":12,"title":"Copy of Template","index":1,"sheetType":"GRID","gridProperties":{"rowCount":10,"columnCount":3}}`)),
+			Header:     make(http.Header),
+		}
+	})
+	wrapper := NewSheetsApiWrapper(mockClient)
+
+	actual, err := wrapper.CopySheetTo(context.Background(), "spreadSheetId", 5, "customerId")
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+	expected := SheetProperties{Id: 12, Title: "Copy of Template", Index: 1, RowCount: 10, ColumnCount: 3}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %+v but found %+v", expected, actual)
+	}
+	if request.URL.Path != "/v4/spreadsheets/spreadSheetId/sheets/5:copyTo" {
+		t.Errorf("unexpected path %s", request.URL.Path)
+	}
+	expectedBody := `{"destinationSpreadsheetId":"customerId"}`
+	if body != expectedBody {
+		t.Errorf("expected body %s but found %s", expectedBody, body)
+	}
+}
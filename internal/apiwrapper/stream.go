@@ -0,0 +1,150 @@
+package apiwrapper
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jo-hoe/google-sheets/gs/a1"
+)
+
+// ValueStream decodes the rows of a values response one by one,
+// so only a single row has to be kept in memory.
+type ValueStream struct {
+	body    io.ReadCloser
+	decoder *json.Decoder
+	// position within the response
+	started  bool
+	inValues bool
+	done     bool
+	// rows of a csv export, see nextCsv
+	csvReader *csv.Reader
+	emptyRows int
+	pending   []any
+}
+
+// StreamRangeValues requests the values of a range and returns a stream of its rows.
+// The caller has to close the stream.
+func (wrapper SheetsApiWrapper) StreamRangeValues(ctx context.Context, spreadSheetId string, a1Range a1.Range) (*ValueStream, error) {
+	if wrapper.publicEndpoint != "" {
+		return wrapper.streamPublicValues(ctx, spreadSheetId, a1Range)
+	}
+	response, err := wrapper.getSheetRequest(ctx, wrapper.url(csvUrlTemplate, spreadSheetId, escapeRange(a1Range))+wrapper.renderQuery())
+	if err != nil {
+		return nil, err
+	}
+	return newValueStream(response), nil
+}
+
+func newValueStream(body io.ReadCloser) *ValueStream {
+	return &ValueStream{
+		body:    body,
+		decoder: json.NewDecoder(body),
+	}
+}
+
+// Next returns the next row of the range, io.EOF after the last row.
+// Cells are either string, float64 or bool. Empty rows within the range are
+// returned as empty slices, trailing empty rows are omitted by the API.
+func (stream *ValueStream) Next() ([]any, error) {
+	if stream.csvReader != nil {
+		return stream.nextCsv()
+	}
+	if stream.done {
+		return nil, io.EOF
+	}
+	if !stream.started {
+		stream.started = true
+		if err := stream.expectDelimiter('{'); err != nil {
+			return nil, stream.fail(err)
+		}
+	}
+
+	for {
+		if stream.inValues {
+			if stream.decoder.More() {
+				row := make([]any, 0)
+				if err := stream.decoder.Decode(&row); err != nil {
+					return nil, stream.fail(err)
+				}
+				return row, nil
+			}
+			if err := stream.expectDelimiter(']'); err != nil {
+				return nil, stream.fail(err)
+			}
+			stream.inValues = false
+		}
+
+		if !stream.decoder.More() {
+			// the object ends without further values
+			if err := stream.expectDelimiter('}'); err != nil {
+				return nil, stream.fail(err)
+			}
+			stream.done = true
+			return nil, io.EOF
+		}
+		key, err := stream.decoder.Token()
+		if err != nil {
+			return nil, stream.fail(err)
+		}
+		if key == "values" {
+			if err := stream.expectDelimiter('['); err != nil {
+				return nil, stream.fail(err)
+			}
+			stream.inValues = true
+			continue
+		}
+		// skip other fields like "range" and "majorDimension"
+		if err := stream.decoder.Decode(&json.RawMessage{}); err != nil {
+			return nil, stream.fail(err)
+		}
+	}
+}
+
+// Close releases the response of the stream
+func (stream *ValueStream) Close() error {
+	stream.done = true
+	return stream.body.Close()
+}
+
+func (stream *ValueStream) expectDelimiter(expected json.Delim) error {
+	token, err := stream.decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != expected {
+		return fmt.Errorf("unexpected token '%v' in values response, expected '%v'", token, expected)
+	}
+	return nil
+}
+
+func (stream *ValueStream) fail(err error) error {
+	stream.done = true
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("could not decode values response: %w", err)
+}
+
+// GetGridSize returns the number of rows and columns of a sheet.
+// If the sheet does not exist, an error matching ErrNotExist is returned.
+func (wrapper SheetsApiWrapper) GetGridSize(ctx context.Context, spreadSheetId string, sheetName string) (rowCount int, columnCount int, err error) {
+	response, err := wrapper.getSheetRequest(ctx, wrapper.url(baseUrl, spreadSheetId))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	result := spreadSheet{}
+	err = deserialize[spreadSheet](response, &result)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, sheet := range result.Sheets {
+		if sheet.Properties.Title == sheetName {
+			return sheet.Properties.GridProperties.RowCount, sheet.Properties.GridProperties.ColumnCount, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("%w: sheet '%s'", ErrNotExist, sheetName)
+}
@@ -0,0 +1,83 @@
+package apiwrapper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jo-hoe/google-sheets/internal/client"
+)
+
+func readStream(stream *ValueStream) ([][]any, error) {
+	result := make([][]any, 0)
+	for {
+		row, err := stream.Next()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+		result = append(result, row)
+	}
+}
+
+func Test_ValueStream(t *testing.T) {
+	tests := map[string][][]any{
+		`{"range":"A1:B3","majorDimension":"ROWS","values":[["a",1],[],[true]]}`: {{"a", 1.0}, {}, {true}},
+		`{"values":[["a"]],"range":"A1:B3","majorDimension":"ROWS"}`:             {{"a"}},
+		`{"range":"A1:B3","majorDimension":"ROWS"}`:                              {},
+		`{"range":"A1:B3","values":[]}`:                                          {},
+		`{"range":{"nested":["a"]},"values":[["b"]]}`:                            {{"b"}},
+	}
+	for body, expected := range tests {
+		stream := newValueStream(io.NopCloser(strings.NewReader(body)))
+		actual, err := readStream(stream)
+		if err != nil {
+			t.Errorf("found error %v for %s", err, body)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected %v but found %v for %s", expected, actual, body)
+		}
+		if _, err = stream.Next(); err != io.EOF {
+			t.Errorf("expected EOF after the last row but found %v", err)
+		}
+	}
+}
+
+func Test_ValueStream_Invalid(t *testing.T) {
+	for _, body := range []string{``, `[]`, `{"values":[["a"]`, `{"values":{"a":1}}`, `{"values":[["a"],"b"]}`} {
+		stream := newValueStream(io.NopCloser(strings.NewReader(body)))
+		_, err := readStream(stream)
+		if err == nil {
+			t.Errorf("expected error for '%s'", body)
+		}
+		if errors.Is(err, io.EOF) {
+			t.Errorf("expected an error other than EOF for '%s' but found %v", body, err)
+		}
+	}
+}
+
+func Test_GetGridSize(t *testing.T) {
+	mockResponse := client.ResponseSummery{
+		ResponseCode: 200,
+		ResponseBody: `{"sheets":[{"properties":{"sheetId":1,"title":"Data","gridProperties":{"rowCount":200000,"columnCount":12}}}]}`,
+	}
+	wrapper := NewSheetsApiWrapper(client.CreateMockClient(mockResponse, mockResponse))
+
+	rowCount, columnCount, err := wrapper.GetGridSize(context.Background(), "spreadSheetId", "Data")
+	if err != nil {
+		t.Fatalf("found error %v", err)
+	}
+	if rowCount != 200000 || columnCount != 12 {
+		t.Errorf("expected 200000 rows and 12 columns but found %d and %d", rowCount, columnCount)
+	}
+
+	_, _, err = wrapper.GetGridSize(context.Background(), "spreadSheetId", "Unknown")
+	if !errors.Is(err, ErrNotExist) {
+		t.Errorf("expected ErrNotExist but found %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"net/http"
+)
+
+// NewAPIKeyClient creates a http client which adds the API key to each request.
+// An API key only grants read access to spreadsheets shared with anyone who has the link.
+// The requests are sent through the transport of the base client, nil uses http.DefaultClient.
+func NewAPIKeyClient(base *http.Client, apiKey string) *http.Client {
+	if base == nil {
+		base = http.DefaultClient
+	}
+	transport := base.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	result := *base
+	result.Transport = &apiKeyTransport{apiKey: apiKey, base: transport}
+	return &result
+}
+
+type apiKeyTransport struct {
+	apiKey string
+	base   http.RoundTripper
+}
+
+func (transport *apiKeyTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request
+	keyed := request.Clone(request.Context())
+	query := keyed.URL.Query()
+	query.Set("key", transport.apiKey)
+	keyed.URL.RawQuery = query.Encode()
+	return transport.base.RoundTrip(keyed)
+}
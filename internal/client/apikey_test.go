@@ -0,0 +1,32 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+)
+
+func Test_NewAPIKeyClient(t *testing.T) {
+	var found string
+	base := NewMockClient(func(request *http.Request) *http.Response {
+		found = request.URL.String()
+		return &http.Response{StatusCode: 200, Body: http.NoBody}
+	})
+
+	request, err := http.NewRequest("GET", "https://sheets.googleapis.com/v4/spreadsheets/id?fields=sheets", nil)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response, err := NewAPIKeyClient(base, "api key").Do(request)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response.Body.Close()
+
+	expected := "https://sheets.googleapis.com/v4/spreadsheets/id?fields=sheets&key=api+key"
+	if found != expected {
+		t.Errorf("expected '%s' but found '%s'", expected, found)
+	}
+	if request.URL.Query().Get("key") != "" {
+		t.Errorf("expected the original request to be unchanged")
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// metadataTokenPath is the path of the access token of the default service account
+// on the metadata server, see https://cloud.google.com/compute/docs/access/authenticate-workloads
+const metadataTokenPath = "computeMetadata/v1/instance/service-accounts/default/token"
+
+// NewDefaultClient creates a http client authenticated with Application Default Credentials.
+// They are looked up in the file of the GOOGLE_APPLICATION_CREDENTIALS environment variable,
+// the user credentials of gcloud and finally the metadata server of GCE, GKE or Cloud Run,
+// see google.FindDefaultCredentials. The subject is only used by service account keys,
+// see NewServiceAccountClient.
+func NewDefaultClient(ctx context.Context, scopes string, subject string) (*http.Client, error) {
+	credentials, err := google.FindDefaultCredentialsWithParams(ctx, google.CredentialsParams{
+		Scopes:  []string{scopes},
+		Subject: subject,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to find default credentials: %v", err)
+	}
+	return oauth2.NewClient(ctx, credentials.TokenSource), nil
+}
+
+// NewMetadataClient creates a http client authenticated with access tokens of the
+// metadata server at the given endpoint, e.g. "http://169.254.169.254/".
+// Tokens are requested when needed and reused until they expire.
+// Like the other token sources of oauth2, the tokens are requested with the http client
+// stored in the context under oauth2.HTTPClient, by default http.DefaultClient.
+func NewMetadataClient(ctx context.Context, metadataEndpoint string, scopes string) (*http.Client, error) {
+	endpoint, err := url.Parse(metadataEndpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid metadata endpoint '%s'", metadataEndpoint)
+	}
+	if !strings.HasSuffix(endpoint.Path, "/") {
+		endpoint.Path = endpoint.Path + "/"
+	}
+	tokenSource := &metadataTokenSource{
+		ctx:      ctx,
+		tokenUrl: endpoint.String() + metadataTokenPath + "?scopes=" + url.QueryEscape(scopes),
+	}
+	return oauth2.NewClient(ctx, oauth2.ReuseTokenSource(nil, tokenSource)), nil
+}
+
+type metadataTokenSource struct {
+	ctx      context.Context
+	tokenUrl string
+}
+
+type metadataTokenJson struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+}
+
+func (source *metadataTokenSource) Token() (*oauth2.Token, error) {
+	request, err := http.NewRequestWithContext(source.ctx, http.MethodGet, source.tokenUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Metadata-Flavor", "Google")
+
+	// returns the client of the context without a token source
+	response, err := oauth2.NewClient(source.ctx, nil).Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to request token from metadata server: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to request token from metadata server: status %s", response.Status)
+	}
+
+	var token metadataTokenJson
+	if err = json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("unable to parse token of metadata server: %v", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("metadata server returned no access token")
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func Test_NewMetadataClient(t *testing.T) {
+	tokenRequests := 0
+	metadataServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/"+metadataTokenPath || r.URL.Query().Get("scopes") != ReadOnlyScopes {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		tokenRequests++
+		fmt.Fprint(w, `{"access_token":"token","expires_in":3599,"token_type":"Bearer"}`)
+	}))
+	defer metadataServer.Close()
+
+	authorizations := make([]string, 0)
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+	}))
+	defer apiServer.Close()
+
+	client, err := NewMetadataClient(context.Background(), metadataServer.URL, ReadOnlyScopes)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	for i := 0; i < 2; i++ {
+		response, err := client.Get(apiServer.URL)
+		if err != nil {
+			t.Fatalf("found error %+v", err)
+		}
+		response.Body.Close()
+	}
+
+	if tokenRequests != 1 {
+		t.Errorf("expected the token to be reused, found %d token requests", tokenRequests)
+	}
+	if len(authorizations) != 2 || authorizations[1] != "Bearer token" {
+		t.Errorf("found authorizations %v", authorizations)
+	}
+}
+
+func Test_NewMetadataClient_Error(t *testing.T) {
+	metadataServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer metadataServer.Close()
+
+	client, err := NewMetadataClient(context.Background(), metadataServer.URL, ReadOnlyScopes)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	_, err = client.Get(metadataServer.URL)
+	if err == nil {
+		t.Errorf("expected error")
+	}
+
+	_, err = NewMetadataClient(context.Background(), "localhost", ReadOnlyScopes)
+	if err == nil {
+		t.Errorf("expected error for endpoint without scheme")
+	}
+}
+
+func Test_NewDefaultClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	credentials := `{"type":"service_account","client_email":"account@project.iam.gserviceaccount.com","private_key":"key","token_uri":"https://oauth2.googleapis.com/token"}`
+	if err := os.WriteFile(path, []byte(credentials), 0600); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", path)
+
+	client, err := NewDefaultClient(context.Background(), ReadOnlyScopes, "")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if client == nil {
+		t.Errorf("expected client")
+	}
+
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", filepath.Join(t.TempDir(), "missing.json"))
+	_, err = NewDefaultClient(context.Background(), ReadOnlyScopes, "")
+	if err == nil {
+		t.Errorf("expected error for missing credentials file")
+	}
+}
+
+func Test_NewMetadataClient_Context_Client(t *testing.T) {
+	requests := make([]string, 0)
+	contextClient := NewMockClient(func(r *http.Request) *http.Response {
+		requests = append(requests, r.URL.Host+r.URL.Path)
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader(`{"access_token":"token","expires_in":3599,"token_type":"Bearer"}`)),
+		}
+	})
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, contextClient)
+
+	client, err := NewMetadataClient(ctx, "http://metadata.test/", ReadOnlyScopes)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response, err := client.Get("http://sheets.test/")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response.Body.Close()
+
+	expected := []string{"metadata.test/" + metadataTokenPath, "sheets.test/"}
+	if len(requests) != 2 || requests[0] != expected[0] || requests[1] != expected[1] {
+		t.Errorf("expected requests %v but found %v", expected, requests)
+	}
+}
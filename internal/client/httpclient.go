@@ -18,17 +18,21 @@ const ReadWriteScopes = "https://www.googleapis.com/auth/spreadsheets"
 // NewReadClient creates a http client to access non-public spreedsheets.
 // Account will only have read access
 func NewReadClient(ctx context.Context, clientCredentialsJson string) (*http.Client, error) {
-	return NewServiceAccountClient(ctx, []byte(clientCredentialsJson), ReadOnlyScopes)
+	return NewServiceAccountClient(ctx, []byte(clientCredentialsJson), ReadOnlyScopes, "")
 }
 
 func NewReadWriteClient(ctx context.Context, clientCredentialsJson string) (*http.Client, error) {
-	return NewServiceAccountClient(ctx, []byte(clientCredentialsJson), ReadWriteScopes)
+	return NewServiceAccountClient(ctx, []byte(clientCredentialsJson), ReadWriteScopes, "")
 }
 
-func NewServiceAccountClient(ctx context.Context, clientCredentials []byte, scopes string) (*http.Client, error) {
+// NewServiceAccountClient creates a http client authenticated with the key of a service account.
+// If a subject is given, the service account acts as this user of the Workspace domain,
+// which requires domain-wide delegation to be granted to the service account.
+func NewServiceAccountClient(ctx context.Context, clientCredentials []byte, scopes string, subject string) (*http.Client, error) {
 	config, err := google.JWTConfigFromJSON(clientCredentials, scopes)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
 	}
+	config.Subject = subject
 	return config.Client(ctx), nil
 }
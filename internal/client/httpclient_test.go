@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_NewServiceAccountClient_Subject(t *testing.T) {
+	claims := make(map[string]any)
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(r.FormValue("assertion"), ".")
+		if len(parts) != 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err == nil {
+			err = json.Unmarshal(payload, &claims)
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer tokenServer.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "account@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    tokenServer.URL,
+	})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	client, err := NewServiceAccountClient(context.Background(), credentials, ReadOnlyScopes, "user@example.com")
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response, err := client.Get(tokenServer.URL)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response.Body.Close()
+
+	if claims["sub"] != "user@example.com" || claims["iss"] != "account@project.iam.gserviceaccount.com" {
+		t.Errorf("found claims %v", claims)
+	}
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// CloudPlatformScope is required by the credentials which impersonate another service account
+const CloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// DefaultIAMCredentialsEndpoint is the base URL of the IAM Service Account Credentials API
+const DefaultIAMCredentialsEndpoint = "https://iamcredentials.googleapis.com/"
+
+// impersonatedTokenLifetime is the lifetime of the requested access tokens, the maximum without org policy changes
+const impersonatedTokenLifetime = "3600s"
+
+// NewImpersonatedClient creates a http client which acts as the target service account.
+// Its access tokens are generated by the IAM Service Account Credentials API, which is
+// called with the base client. The account of the base client needs the role
+// "Service Account Token Creator" on the target, or on the last of the delegates
+// which form a chain of service accounts from the base account to the target.
+// The base client needs the scope CloudPlatformScope.
+// The requests of the returned client are sent through the transport of the base client,
+// e.g. a proxy, with the authentication of the base client replaced if it is an oauth2.Transport.
+// An empty endpoint uses DefaultIAMCredentialsEndpoint.
+func NewImpersonatedClient(ctx context.Context, base *http.Client, endpoint string, targetServiceAccount string, delegates []string, scopes string) (*http.Client, error) {
+	if targetServiceAccount == "" {
+		return nil, fmt.Errorf("no service account to impersonate")
+	}
+	if endpoint == "" {
+		endpoint = DefaultIAMCredentialsEndpoint
+	}
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint = endpoint + "/"
+	}
+	tokenSource := &impersonatedTokenSource{
+		ctx:       ctx,
+		base:      base,
+		tokenUrl:  endpoint + "v1/" + serviceAccountResource(targetServiceAccount) + ":generateAccessToken",
+		delegates: make([]string, 0, len(delegates)),
+		scopes:    scopes,
+	}
+	for _, delegate := range delegates {
+		tokenSource.delegates = append(tokenSource.delegates, serviceAccountResource(delegate))
+	}
+	return newTokenClient(base, oauth2.ReuseTokenSource(nil, tokenSource)), nil
+}
+
+// newTokenClient creates a client which authenticates its requests with the token source
+// and sends them through the transport of the base client. The token of an oauth2.Transport
+// of the base client would replace the one of the source, so its base transport is used.
+func newTokenClient(base *http.Client, tokenSource oauth2.TokenSource) *http.Client {
+	if base == nil {
+		base = http.DefaultClient
+	}
+	transport := base.Transport
+	if authenticated, ok := transport.(*oauth2.Transport); ok {
+		transport = authenticated.Base
+	}
+	result := *base
+	result.Transport = &oauth2.Transport{Source: tokenSource, Base: transport}
+	return &result
+}
+
+func serviceAccountResource(email string) string {
+	return "projects/-/serviceAccounts/" + url.PathEscape(email)
+}
+
+type impersonatedTokenSource struct {
+	ctx       context.Context
+	base      *http.Client
+	tokenUrl  string
+	delegates []string
+	scopes    string
+}
+
+type generateAccessTokenRequestJson struct {
+	Delegates []string `json:"delegates,omitempty"`
+	Scope     []string `json:"scope"`
+	Lifetime  string   `json:"lifetime"`
+}
+
+type generateAccessTokenResponseJson struct {
+	AccessToken string    `json:"accessToken"`
+	ExpireTime  time.Time `json:"expireTime"`
+}
+
+func (source *impersonatedTokenSource) Token() (*oauth2.Token, error) {
+	body, err := json.Marshal(generateAccessTokenRequestJson{
+		Delegates: source.delegates,
+		Scope:     []string{source.scopes},
+		Lifetime:  impersonatedTokenLifetime,
+	})
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(source.ctx, http.MethodPost, source.tokenUrl, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := source.base.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate access token: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1<<10))
+		return nil, fmt.Errorf("unable to generate access token: status %s: %s", response.Status, strings.TrimSpace(string(message)))
+	}
+
+	var token generateAccessTokenResponseJson
+	if err = json.NewDecoder(response.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("unable to parse generated access token: %v", err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      token.ExpireTime,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func Test_NewImpersonatedClient(t *testing.T) {
+	var request generateAccessTokenRequestJson
+	iamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer base" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/projects/-/serviceAccounts/target@project.iam.gserviceaccount.com:generateAccessToken" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"accessToken":"impersonated","expireTime":"2100-01-01T00:00:00Z"}`)
+	}))
+	defer iamServer.Close()
+
+	authorization := ""
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer apiServer.Close()
+
+	base := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "base"}))
+	client, err := NewImpersonatedClient(context.Background(), base, iamServer.URL, "target@project.iam.gserviceaccount.com",
+		[]string{"delegate@project.iam.gserviceaccount.com"}, ReadWriteScopes)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response, err := client.Get(apiServer.URL)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response.Body.Close()
+
+	if authorization != "Bearer impersonated" {
+		t.Errorf("found authorization '%s'", authorization)
+	}
+	expected := generateAccessTokenRequestJson{
+		Delegates: []string{"projects/-/serviceAccounts/delegate@project.iam.gserviceaccount.com"},
+		Scope:     []string{ReadWriteScopes},
+		Lifetime:  "3600s",
+	}
+	if !reflect.DeepEqual(expected, request) {
+		t.Errorf("expected %+v, found %+v", expected, request)
+	}
+}
+
+func Test_NewImpersonatedClient_Denied(t *testing.T) {
+	iamServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error":{"code":403,"message":"Permission 'iam.serviceAccounts.getAccessToken' denied"}}`)
+	}))
+	defer iamServer.Close()
+
+	client, err := NewImpersonatedClient(context.Background(), http.DefaultClient, iamServer.URL, "target@project.iam.gserviceaccount.com", nil, ReadOnlyScopes)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	_, err = client.Get(iamServer.URL)
+	if err == nil {
+		t.Errorf("expected error")
+	}
+
+	_, err = NewImpersonatedClient(context.Background(), http.DefaultClient, "", "", nil, ReadOnlyScopes)
+	if err == nil {
+		t.Errorf("expected error without target service account")
+	}
+}
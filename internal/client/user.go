@@ -0,0 +1,258 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// NewUserClient creates a http client which accesses the sheets as the user who authorizes it.
+// The client secret is the JSON file of an OAuth client of type "Desktop app".
+//
+// If the token cache file contains a token granted for the scopes, it is reused. A token
+// with the read and write scope is reused for read-only access as well. Otherwise the user is asked
+// to open the authorization url, which is passed to openURL, and to grant access.
+// The browser is redirected to a listener on 127.0.0.1 which receives the authorization code.
+// The code is protected by PKCE. The token is stored in the cache file with permissions 0600,
+// refreshed tokens are stored as well. An empty cache file name disables caching.
+func NewUserClient(ctx context.Context, clientSecret []byte, scopes string, tokenCacheFile string, openURL func(authURL string) error) (*http.Client, error) {
+	config, err := google.ConfigFromJSON(clientSecret, scopes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse client secret file to config: %v", err)
+	}
+
+	token, granted, err := loadToken(tokenCacheFile, scopes)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		token, err = authorize(ctx, config, openURL)
+		if err != nil {
+			return nil, err
+		}
+		granted = grantedScopes(token, scopes)
+		if err = saveToken(tokenCacheFile, token, granted); err != nil {
+			return nil, err
+		}
+	}
+
+	tokenSource := &cachingTokenSource{
+		base:   config.TokenSource(ctx, token),
+		path:   tokenCacheFile,
+		scopes: granted,
+		token:  token,
+	}
+	return oauth2.NewClient(ctx, tokenSource), nil
+}
+
+// authorize runs the loopback redirect flow and exchanges the received code for a token
+func authorize(ctx context.Context, config *oauth2.Config, openURL func(authURL string) error) (*oauth2.Token, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen for the authorization code: %v", err)
+	}
+	defer listener.Close()
+
+	redirectConfig := *config
+	redirectConfig.RedirectURL = "http://" + listener.Addr().String() + "/"
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+		if message := query.Get("error"); message != "" {
+			http.Error(w, "authorization failed: "+message, http.StatusForbidden)
+			sendOnce(errs, fmt.Errorf("authorization failed: %s", message))
+			return
+		}
+		code := query.Get("code")
+		if code == "" {
+			http.Error(w, "missing authorization code", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, "Authorization completed, you can close this window.")
+		sendOnce(codes, code)
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authURL := redirectConfig.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
+	if openURL == nil {
+		openURL = printURL
+	}
+	if err = openURL(authURL); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err = <-errs:
+		return nil, err
+	case code := <-codes:
+		token, err := redirectConfig.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+		if err != nil {
+			return nil, fmt.Errorf("unable to exchange authorization code: %w", err)
+		}
+		return token, nil
+	}
+}
+
+func printURL(authURL string) error {
+	_, err := fmt.Fprintf(os.Stderr, "Open the following URL in your browser to grant access:\n%s\n", authURL)
+	return err
+}
+
+func sendOnce[T any](channel chan T, value T) {
+	select {
+	case channel <- value:
+	default:
+	}
+}
+
+func randomState() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// cachedToken is the content of the token cache file
+type cachedToken struct {
+	*oauth2.Token
+	// space separated scopes the token was granted for
+	Scopes string `json:"scopes"`
+}
+
+// loadToken reads a cached token and the scopes it was granted for. nil is returned
+// if there is none or if it was not granted for the scopes, e.g. a read-only token
+// which is requested for write access.
+func loadToken(path string, scopes string) (*oauth2.Token, string, error) {
+	if path == "" {
+		return nil, "", nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to read token cache: %w", err)
+	}
+	cached := cachedToken{Token: &oauth2.Token{}}
+	if err = json.Unmarshal(data, &cached); err != nil {
+		return nil, "", fmt.Errorf("unable to parse token cache '%s': %v", path, err)
+	}
+	if cached.RefreshToken == "" && !cached.Valid() {
+		return nil, "", nil
+	}
+	if !coversScopes(cached.Scopes, scopes) {
+		return nil, "", nil
+	}
+	return cached.Token, cached.Scopes, nil
+}
+
+// grantedScopes returns the scopes in the token response, which may lack scopes the user
+// did not grant, or the requested scopes if the response does not contain them
+func grantedScopes(token *oauth2.Token, requested string) string {
+	if granted, ok := token.Extra("scope").(string); ok && granted != "" {
+		return granted
+	}
+	return requested
+}
+
+// coversScopes returns true if all requested scopes are granted. The read and write
+// scope includes the read-only scope.
+func coversScopes(granted string, requested string) bool {
+	grantedSet := make(map[string]bool)
+	for _, scope := range strings.Fields(granted) {
+		grantedSet[scope] = true
+	}
+	for _, scope := range strings.Fields(requested) {
+		if !grantedSet[scope] && !(scope == ReadOnlyScopes && grantedSet[ReadWriteScopes]) {
+			return false
+		}
+	}
+	return true
+}
+
+// saveToken replaces the token cache, the file is only readable by the current user
+func saveToken(path string, token *oauth2.Token, scopes string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(cachedToken{Token: token, Scopes: scopes})
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("unable to write token cache: %w", err)
+	}
+	// a temporary file is created with permissions 0600 and renamed, so the cache is never partially written
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write token cache: %w", err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0600)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write token cache: %w", err)
+	}
+	return nil
+}
+
+// cachingTokenSource stores tokens of the base source in the cache file when they change
+type cachingTokenSource struct {
+	base   oauth2.TokenSource
+	path   string
+	scopes string
+	mutex  sync.Mutex
+	token  *oauth2.Token
+}
+
+func (source *cachingTokenSource) Token() (*oauth2.Token, error) {
+	token, err := source.base.Token()
+	if err != nil {
+		return nil, err
+	}
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	if source.token == nil || source.token.AccessToken != token.AccessToken {
+		if err = saveToken(source.path, token, source.scopes); err != nil {
+			return nil, err
+		}
+		source.token = token
+	}
+	return token, nil
+}
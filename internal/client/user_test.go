@@ -0,0 +1,245 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// fakeOAuthServer issues tokens for the authorization codes of the loopback flow
+type fakeOAuthServer struct {
+	*httptest.Server
+	challenge     string
+	grants        []string
+	authorization string
+}
+
+func newFakeOAuthServer() *fakeOAuthServer {
+	server := &fakeOAuthServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			server.handleToken(w, r)
+		default:
+			server.authorization = r.Header.Get("Authorization")
+		}
+	}))
+	return server
+}
+
+func (server *fakeOAuthServer) handleToken(w http.ResponseWriter, r *http.Request) {
+	grantType := r.FormValue("grant_type")
+	server.grants = append(server.grants, grantType)
+	switch grantType {
+	case "authorization_code":
+		hash := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if r.FormValue("code") != "code" || base64.RawURLEncoding.EncodeToString(hash[:]) != server.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"access","refresh_token":"refresh","token_type":"Bearer","expires_in":3600}`)
+	case "refresh_token":
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"refreshed","token_type":"Bearer","expires_in":3600}`)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (server *fakeOAuthServer) clientSecret() []byte {
+	return []byte(fmt.Sprintf(`{"installed":{"client_id":"id","client_secret":"secret","auth_uri":"%s/auth","token_uri":"%s/token","redirect_uris":["http://localhost"]}}`, server.URL, server.URL))
+}
+
+// approve acts as the browser of a user who grants access
+func (server *fakeOAuthServer) approve(t *testing.T) func(authURL string) error {
+	return func(authURL string) error {
+		parsed, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		query := parsed.Query()
+		if query.Get("code_challenge_method") != "S256" || query.Get("access_type") != "offline" {
+			t.Errorf("unexpected authorization url %s", authURL)
+		}
+		server.challenge = query.Get("code_challenge")
+		redirect := query.Get("redirect_uri") + "?code=code&state=" + url.QueryEscape(query.Get("state"))
+		go func() {
+			response, err := http.Get(redirect)
+			if err == nil {
+				response.Body.Close()
+			}
+		}()
+		return nil
+	}
+}
+
+func Test_NewUserClient(t *testing.T) {
+	server := newFakeOAuthServer()
+	defer server.Close()
+	cacheFile := filepath.Join(t.TempDir(), "gsheets", "token.json")
+
+	client, err := NewUserClient(context.Background(), server.clientSecret(), ReadOnlyScopes, cacheFile, server.approve(t))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response.Body.Close()
+	if server.authorization != "Bearer access" {
+		t.Errorf("found authorization '%s'", server.authorization)
+	}
+
+	info, err := os.Stat(cacheFile)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected permissions 0600, found %v", info.Mode().Perm())
+	}
+
+	// the cached token is reused without asking the user again
+	_, err = NewUserClient(context.Background(), server.clientSecret(), ReadOnlyScopes, cacheFile, func(string) error {
+		t.Errorf("expected cached token to be used")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if len(server.grants) != 1 {
+		t.Errorf("expected a single token request, found %v", server.grants)
+	}
+}
+
+func Test_NewUserClient_Refresh(t *testing.T) {
+	server := newFakeOAuthServer()
+	defer server.Close()
+	cacheFile := filepath.Join(t.TempDir(), "token.json")
+	expired := &oauth2.Token{AccessToken: "expired", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)}
+	if err := saveToken(cacheFile, expired, ReadOnlyScopes); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	client, err := NewUserClient(context.Background(), server.clientSecret(), ReadOnlyScopes, cacheFile, func(string) error {
+		t.Errorf("expected refresh token to be used")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	response.Body.Close()
+	if server.authorization != "Bearer refreshed" {
+		t.Errorf("found authorization '%s'", server.authorization)
+	}
+
+	cached := &oauth2.Token{}
+	data, err := os.ReadFile(cacheFile)
+	if err == nil {
+		err = json.Unmarshal(data, cached)
+	}
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if cached.AccessToken != "refreshed" || cached.RefreshToken != "refresh" {
+		t.Errorf("expected refreshed token to be cached, found %+v", cached)
+	}
+}
+
+func Test_NewUserClient_Denied(t *testing.T) {
+	server := newFakeOAuthServer()
+	defer server.Close()
+
+	_, err := NewUserClient(context.Background(), server.clientSecret(), ReadOnlyScopes, "", func(authURL string) error {
+		parsed, _ := url.Parse(authURL)
+		redirect := parsed.Query().Get("redirect_uri") + "?error=access_denied&state=" + url.QueryEscape(parsed.Query().Get("state"))
+		go func() {
+			response, err := http.Get(redirect)
+			if err == nil {
+				response.Body.Close()
+			}
+		}()
+		return nil
+	})
+	if err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func Test_NewUserClient_Canceled(t *testing.T) {
+	server := newFakeOAuthServer()
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	_, err := NewUserClient(ctx, server.clientSecret(), ReadOnlyScopes, "", func(string) error {
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("expected %v, found %v", context.Canceled, err)
+	}
+}
+
+func Test_NewUserClient_Scopes(t *testing.T) {
+	server := newFakeOAuthServer()
+	defer server.Close()
+	cacheFile := filepath.Join(t.TempDir(), "token.json")
+	token := &oauth2.Token{AccessToken: "read", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}
+	if err := saveToken(cacheFile, token, ReadOnlyScopes); err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+
+	// a read-only token is not reused for write access
+	_, err := NewUserClient(context.Background(), server.clientSecret(), ReadWriteScopes, cacheFile, server.approve(t))
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+	if len(server.grants) != 1 || server.grants[0] != "authorization_code" {
+		t.Errorf("expected the user to authorize again, found token requests %v", server.grants)
+	}
+
+	// the read and write token is reused for read-only access
+	_, err = NewUserClient(context.Background(), server.clientSecret(), ReadOnlyScopes, cacheFile, func(string) error {
+		t.Errorf("expected cached token to be used")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("found error %+v", err)
+	}
+}
+
+func Test_coversScopes(t *testing.T) {
+	tests := []struct {
+		granted   string
+		requested string
+		expected  bool
+	}{
+		{ReadOnlyScopes, ReadOnlyScopes, true},
+		{ReadWriteScopes, ReadOnlyScopes, true},
+		{ReadOnlyScopes, ReadWriteScopes, false},
+		{"", ReadOnlyScopes, false},
+		{ReadOnlyScopes + " " + CloudPlatformScope, CloudPlatformScope, true},
+	}
+	for _, test := range tests {
+		if actual := coversScopes(test.granted, test.requested); actual != test.expected {
+			t.Errorf("expected %v for '%s' and '%s'", test.expected, test.granted, test.requested)
+		}
+	}
+}